@@ -65,19 +65,36 @@
 //
 // Aperture emits diagnostic signals for operational visibility:
 //   - [SignalMetricValueMissing]: Metric event lacks required value field
+//   - [SignalMetricValueInvalid]: String metric value could not be parsed as a number
+//   - [SignalMetricFilterMissing]: Metric filter field missing from event
+//   - [SignalMetricHistogramTypeChanged]: Apply changed a histogram_type the meter provider cannot follow
 //   - [SignalTraceExpired]: Span start/end never matched within timeout
 //   - [SignalTraceCorrelationMissing]: Trace event lacks correlation ID field
-//
-// These appear as DEBUG-level logs with "aperture.signal" attribute.
+//   - [SignalTraceDuplicateStart]: Start event arrived for an already pending span
+//   - [SignalTraceDuplicateEnd]: Second end signal arrived for the same span
+//   - [SignalTraceNegativeDuration]: End timestamp preceded start; span clamped to zero duration
+//   - [SignalTracePendingOverflow]: Pending start or end rejected because max_pending was reached
+//   - [SignalValueClamped]: Unsigned field value exceeded max int64 and was clamped
+//   - [SignalTransformFailed]: Custom field value could not be JSON serialized
+//   - [SignalServiceNameMissing]: Trace provider resource has no service.name
+//   - [SignalConfigError]: Metric instrument could not be created; skipped (best_effort)
+//   - [SignalConfigUnused]: Configured signal not seen within the warmup window (opt-in)
+//
+// These appear as DEBUG-level logs with an "aperture.signal" attribute prefixed
+// "aperture:". Event log records carry the same attribute with their own signal name.
 package aperture
 
 import (
 	"context"
 	"fmt"
+	"maps"
+	"reflect"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/zoobzio/capitan"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
@@ -89,16 +106,27 @@ type Aperture struct {
 	logProvider   log.LoggerProvider
 	meterProvider metric.MeterProvider
 	traceProvider trace.TracerProvider
+	pendingStarts PendingStore // nil = in-memory per traces handler
+	pendingEnds   PendingStore
 
 	// Pointers and maps (8 bytes each)
 	capitan          *capitan.Capitan
 	contextKeys      map[string]any // name → context key for ctx.Value()
+	logTransforms    map[string]func(any) []log.KeyValue
+	metricTransforms map[string]func(any) []attribute.KeyValue
 	capitanObserver  *capitanObserver
 	internalObserver *internalObserver
 
+	// Slices (pointer in first 8 bytes)
+	staticAttrs []attribute.KeyValue // added to every log, metric, and span
+
 	// Embedded struct
 	config config
 
+	// needsRebuild is set by setters whose state is read when the observer is
+	// built, so the next Apply rebuilds even if the config is unchanged.
+	needsRebuild bool
+
 	mu sync.RWMutex
 }
 
@@ -142,16 +170,18 @@ func New(
 	}
 
 	s := &Aperture{
-		capitan:       c,
-		logProvider:   logProvider,
-		meterProvider: meterProvider,
-		traceProvider: traceProvider,
-		config:        config{},
-		contextKeys:   make(map[string]any),
+		capitan:          c,
+		logProvider:      logProvider,
+		meterProvider:    meterProvider,
+		traceProvider:    traceProvider,
+		config:           config{},
+		contextKeys:      make(map[string]any),
+		logTransforms:    make(map[string]func(any) []log.KeyValue),
+		metricTransforms: make(map[string]func(any) []attribute.KeyValue),
 	}
 
 	// Create internal diagnostic observer
-	s.internalObserver = newInternalObserver(s.logProvider.Logger("aperture.internal"))
+	s.internalObserver = newInternalObserver(s.logProvider.Logger(s.config.internalLoggerName()))
 
 	// Attach capitan observer
 	observer, err := newCapitanObserver(s, c)
@@ -186,6 +216,60 @@ func (s *Aperture) RegisterContextKey(name string, key any) {
 	s.contextKeys[name] = key
 }
 
+// RegisterContextKeys registers several context keys at once, mapping each
+// schema name to its context key. It is equivalent to calling
+// [Aperture.RegisterContextKey] for every entry; existing names are replaced.
+//
+// Example:
+//
+//	ap.RegisterContextKeys(map[string]any{
+//	    "user_id":    userIDKey,
+//	    "request_id": requestIDKey,
+//	})
+func (s *Aperture) RegisterContextKeys(keys map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	maps.Copy(s.contextKeys, keys)
+}
+
+// RegisterContextTransformer sets how the context value registered under name
+// becomes log attributes, e.g. expanding a request metadata struct into one
+// attribute per field. Without a transformer, values of unknown types are
+// logged as a single JSON string.
+//
+// The transformer is used for every value of the key, and takes effect on the
+// next [Aperture.Apply]. It is not used for stdout logging.
+//
+// Example:
+//
+//	ap.RegisterContextTransformer("request_meta", func(v any) []log.KeyValue {
+//	    meta, ok := v.(RequestMeta)
+//	    if !ok {
+//	        return nil
+//	    }
+//	    return []log.KeyValue{
+//	        log.String("request.id", meta.ID),
+//	        log.String("request.route", meta.Route),
+//	    }
+//	})
+func (s *Aperture) RegisterContextTransformer(name string, fn func(any) []log.KeyValue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logTransforms[name] = fn
+	s.needsRebuild = true
+}
+
+// RegisterContextMetricTransformer is the metric and trace counterpart of
+// [Aperture.RegisterContextTransformer]. The returned attributes are added to
+// metric dimensions and span attributes for the context value registered
+// under name.
+func (s *Aperture) RegisterContextMetricTransformer(name string, fn func(any) []attribute.KeyValue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metricTransforms[name] = fn
+	s.needsRebuild = true
+}
+
 // Logger returns an OTEL logger for the given scope name.
 //
 // The scope name typically represents the package or component emitting logs.
@@ -207,11 +291,161 @@ func (s *Aperture) Tracer(name string) trace.Tracer {
 	return s.traceProvider.Tracer(name)
 }
 
+// SetLogFilter replaces the log whitelist and blacklist without a full [Aperture.Apply].
+//
+// Only the compiled log filter on the live observer is swapped; metric instruments
+// and pending spans are left untouched, and no drain is performed. The new filter
+// takes effect for the next event processed. Passing two empty lists logs everything.
+//
+// The filter is replaced again by the schema on the next Apply.
+//
+// Example:
+//
+//	ap.SetLogFilter([]string{"order.created"}, nil)
+func (s *Aperture) SetLogFilter(whitelist, blacklist []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Replace only the lists; other log settings survive the swap
+	var logs logConfig
+	if s.config.Logs != nil {
+		logs = *s.config.Logs
+	}
+	logs.WhitelistNames = whitelist
+	logs.BlacklistNames = blacklist
+	s.config.Logs = nil
+	if !logs.isZero() {
+		s.config.Logs = &logs
+	}
+
+	if s.capitanObserver != nil {
+		s.capitanObserver.logFilter.Store(newLogFilter(whitelist, blacklist))
+	}
+}
+
+// SetLogFilterSignals is [Aperture.SetLogFilter] for signal values instead of
+// names. Each signal is matched by its Name(), exactly as the schema's names
+// are, so a program can pass the signals it emits without restating them.
+//
+// Example:
+//
+//	ap.SetLogFilterSignals([]capitan.Signal{OrderCreated}, nil)
+func (s *Aperture) SetLogFilterSignals(whitelist, blacklist []capitan.Signal) {
+	s.SetLogFilter(signalNames(whitelist), signalNames(blacklist))
+}
+
+// signalNames returns the names of signals, or nil if there are none.
+func signalNames(signals []capitan.Signal) []string {
+	if len(signals) == 0 {
+		return nil
+	}
+	names := make([]string, len(signals))
+	for i, sig := range signals {
+		names[i] = sig.Name()
+	}
+	return names
+}
+
+// WithStaticAttributes sets attributes added to every log record, metric measurement,
+// and span produced by this Aperture instance.
+//
+// This is useful when several logical components share one set of providers and
+// need to be told apart without a separate resource. The attributes merge with
+// per-event fields; an event field with the same key takes precedence. Each call
+// replaces the previous set, and calling with no attributes clears it.
+//
+// Like [Aperture.SetLogFilter], the change is applied to the live observer without
+// a drain and persists across subsequent Apply calls.
+//
+// Example:
+//
+//	ap.WithStaticAttributes(attribute.String("component", "billing"))
+func (s *Aperture) WithStaticAttributes(attrs ...attribute.KeyValue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.staticAttrs = slices.Clone(attrs)
+
+	if s.capitanObserver != nil {
+		s.capitanObserver.staticAttrs.Store(newStaticAttributes(s.staticAttrs))
+	}
+}
+
+// SetPendingStores sets where trace start and end events wait for their
+// counterpart. Passing nil for either restores the default in-memory store.
+//
+// The stores take effect on the next [Aperture.Apply] and are shared by every
+// traces handler built after it. Unlike the default stores, which are
+// discarded on each Apply, custom stores keep their events across Apply and
+// Close. A store backed by an external database therefore lets spans whose
+// start and end straddle a restart still correlate. Event contexts cannot be
+// persisted, so such spans have no parent and no context attributes.
+func (s *Aperture) SetPendingStores(starts, ends PendingStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pendingStarts = starts
+	s.pendingEnds = ends
+	s.needsRebuild = true
+}
+
+// OnDiagnostic registers fn to be called whenever an internal diagnostic fires,
+// such as [SignalMetricValueMissing], in addition to its log record on the
+// "aperture.internal" logger. fn receives the diagnostic signal and its fields,
+// e.g. to increment an application error metric. Passing nil removes the
+// callback.
+//
+// fn runs on the diagnostic observer's goroutine, one diagnostic at a time, and
+// must not block.
+func (s *Aperture) OnDiagnostic(fn func(signal capitan.Signal, fields []capitan.Field)) {
+	if fn == nil {
+		s.internalObserver.callback.Store(nil)
+		return
+	}
+	s.internalObserver.callback.Store(&fn)
+}
+
+// PendingSpanCount returns the number of trace start and end events waiting for
+// their counterpart. Both are zero when no traces are configured.
+//
+// Counts that grow steadily usually mean a correlation key mismatch: starts or
+// ends that never pair up are held until their span timeout. Set
+// Schema.PendingSpanMetric to also export these counts as a gauge.
+func (s *Aperture) PendingSpanCount() (starts, ends int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.capitanObserver == nil {
+		return 0, 0
+	}
+	return s.capitanObserver.tracesHandler.pendingCounts()
+}
+
+// PendingSpanTotal returns the total number of pending trace starts and ends,
+// for health and readiness checks. It is zero when no traces are configured.
+// Use [Aperture.PendingSpanCount] for the split by kind.
+func (s *Aperture) PendingSpanTotal() int {
+	starts, ends := s.PendingSpanCount()
+	return starts + ends
+}
+
+// MetricNames returns the names of all currently configured metric instruments, sorted.
+//
+// This is read-only introspection intended for debugging: if a metric never shows
+// up in your backend, MetricNames tells you whether the instrument was created at all.
+func (s *Aperture) MetricNames() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.capitanObserver == nil {
+		return nil
+	}
+	return s.capitanObserver.metricsHandler.names()
+}
+
 // Apply updates the aperture configuration atomically.
 //
-// This drains the current observer (waiting for queued events to complete),
-// then creates a new one with the updated config. No events are lost during
-// the transition.
+// It is equivalent to [Aperture.ApplyWithContext] with context.Background(),
+// so the drain of the current observer has no deadline.
 //
 // Example with flux for hot-reload:
 //
@@ -223,6 +457,60 @@ func (s *Aperture) Tracer(name string) trace.Tracer {
 //	)
 //	capacitor.Start(ctx)
 func (s *Aperture) Apply(schema Schema) error {
+	return s.ApplyWithContext(context.Background(), schema)
+}
+
+// Validate checks schema as [Aperture.Apply] would, without applying it.
+//
+// Beyond [Schema.Validate], it resolves context key names against the keys
+// registered with [Aperture.RegisterContextKey]. It has no side effects, so
+// hot-reload callbacks can reject a bad config before Apply drains the running
+// observer. Errors from creating metric instruments are only reported by Apply,
+// which rolls back to the previous configuration.
+func (s *Aperture) Validate(schema Schema) error {
+	if err := schema.Validate(); err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, err := s.buildConfig(schema); err != nil {
+		return fmt.Errorf("building config: %w", err)
+	}
+	return nil
+}
+
+// Reset reverts to the default log-all behavior: no metrics, traces, log
+// filtering, or context extraction. The current observer is drained first, and
+// pending spans are discarded.
+//
+// It is equivalent to Apply(Schema{}). Instance-wide settings made outside the
+// schema, such as registered context keys and static attributes, are kept.
+func (s *Aperture) Reset() error {
+	return s.Apply(Schema{})
+}
+
+// ApplyWithContext updates the aperture configuration atomically, bounding the
+// drain of the current observer by ctx.
+//
+// The new observer is fully built first. Only then is the current observer
+// drained (waiting for queued events to complete) and replaced. No events are
+// lost during the transition. If anything fails, including ctx expiring before
+// the drain completes, the previous config and observer stay in place and keep
+// processing events.
+//
+// If the schema builds the same config as the one in effect, as when a config
+// file is touched but not changed, nothing is drained or rebuilt and nil is
+// returned. Pending stores or context transformers set since the last Apply
+// always cause a rebuild.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	err := ap.ApplyWithContext(ctx, schema)
+func (s *Aperture) ApplyWithContext(ctx context.Context, schema Schema) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -231,48 +519,121 @@ func (s *Aperture) Apply(schema Schema) error {
 		return fmt.Errorf("invalid schema: %w", err)
 	}
 
+	// The lock may have been held by another Apply long enough for ctx to expire
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("applying schema: %w", err)
+	}
+
 	// Build internal config from schema
 	cfg, err := s.buildConfig(schema)
 	if err != nil {
 		return fmt.Errorf("building config: %w", err)
 	}
 
+	// An unchanged config keeps the live observer, sparing a drain, unless
+	// state outside the config has changed since it was built. Configs
+	// holding context key transforms never compare equal, so they rebuild.
+	if s.capitanObserver != nil && !s.needsRebuild && reflect.DeepEqual(*cfg, s.config) {
+		return nil
+	}
+
+	// Build the new observer before touching the live one
+	prev := s.config
+	s.config = *cfg
+	observer, err := buildCapitanObserver(s)
+	if err != nil {
+		s.config = prev
+		return fmt.Errorf("creating observer: %w", err)
+	}
+
 	// Drain and close old observer
 	if s.capitanObserver != nil {
-		// Drain waits for all queued events to be processed
-		if drainErr := s.capitanObserver.Drain(context.Background()); drainErr != nil {
+		// Drain waits for all queued events to be processed, or for ctx to expire
+		if drainErr := s.capitanObserver.Drain(ctx); drainErr != nil {
+			observer.Close()
+			s.config = prev
 			return fmt.Errorf("draining observer: %w", drainErr)
 		}
 		s.capitanObserver.Close()
 	}
 
-	// Update config
-	s.config = *cfg
-
-	// Create new observer with updated config
-	observer, err := newCapitanObserver(s, s.capitan)
-	if err != nil {
-		return fmt.Errorf("creating observer: %w", err)
-	}
+	observer.attach(s.capitan)
 	s.capitanObserver = observer
+	s.needsRebuild = false
+	s.internalObserver.setLogger(s.logProvider.Logger(s.config.internalLoggerName()))
+	s.reportHistogramTypeChanges(prev.Metrics)
 
 	return nil
 }
 
+// ApplyAll merges schemas with [Schema.Merge] and applies the result in a single
+// drain/rebuild cycle.
+//
+// This suits configuration split across files (e.g. metrics.yaml and traces.yaml).
+// Each schema is validated on its own before merging, then the merged schema is
+// validated as a whole. Any failure aborts without touching the running observer.
+//
+// Example:
+//
+//	metrics, _ := aperture.LoadSchemaFromYAML(metricsYAML)
+//	traces, _ := aperture.LoadSchemaFromYAML(tracesYAML)
+//	err := ap.ApplyAll(metrics, traces)
+func (s *Aperture) ApplyAll(schemas ...Schema) error {
+	var merged Schema
+	for i, schema := range schemas {
+		if err := schema.Validate(); err != nil {
+			return fmt.Errorf("invalid schema %d: %w", i, err)
+		}
+		merged = merged.Merge(schema)
+	}
+
+	return s.Apply(merged)
+}
+
 // buildConfig converts a Schema to internal config.
 func (s *Aperture) buildConfig(schema Schema) (*config, error) {
 	cfg := &config{
-		StdoutLogging: schema.Stdout,
+		StdoutLogging:        schema.Stdout,
+		StdoutFormat:         parseStdoutFormat(schema.StdoutFormat),
+		ScopeName:            schema.ScopeName,
+		ExcludeFields:        schema.ExcludeFields,
+		StrictWhitelist:      schema.StrictWhitelist,
+		PendingSpanMetric:    schema.PendingSpanMetric,
+		LogsEmittedMetric:    schema.LogsEmittedMetric,
+		BestEffort:           schema.BestEffort,
+		KeepUnserializable:   schema.KeepUnserializable,
+		SortAttributes:       schema.SortAttributes,
+		MirrorMetaAttributes: schema.MirrorMetaAttributes,
+		MaxPending:           schema.MaxPending,
+		MaxAttributeBytes:    schema.MaxAttributeBytes,
+	}
+
+	// Validate has already checked the warmup parses
+	if schema.UnusedConfigWarmup != "" {
+		cfg.UnusedConfigWarmup, _ = time.ParseDuration(schema.UnusedConfigWarmup) //nolint:errcheck // validated
 	}
 
 	// Convert metrics
 	for _, m := range schema.Metrics {
 		mc := metricConfig{
-			SignalName:   m.Signal,
-			Name:         m.Name,
-			Type:         parseMetricType(m.Type),
-			ValueKeyName: m.ValueKey,
-			Description:  m.Description,
+			SignalName:               m.Signal,
+			Name:                     m.Name,
+			Type:                     parseMetricType(m.Type),
+			ValueKeyName:             m.ValueKey,
+			Description:              m.Description,
+			Unit:                     m.Unit,
+			DurationUnit:             DurationUnit(m.DurationUnit),
+			Aggregation:              GaugeAggregation(m.Aggregation),
+			Attributes:               m.Attributes,
+			ParseStringValue:         m.ParseStringValue,
+			AttributeRename:          m.AttributeRename,
+			AttributeAllowlist:       m.AttributeAllowlist,
+			IncludeSeverityAttribute: m.IncludeSeverityAttribute,
+			IncludeSignalAttribute:   m.IncludeSignalAttribute,
+			HistogramType:            HistogramType(m.HistogramType),
+			Buckets:                  resolveBuckets(m),
+			FilterKeyName:            m.FilterKey,
+			FilterValue:              m.FilterValue,
 		}
 		cfg.Metrics = append(cfg.Metrics, mc)
 	}
@@ -281,18 +642,30 @@ func (s *Aperture) buildConfig(schema Schema) (*config, error) {
 	for _, t := range schema.Traces {
 		tc := traceConfig{
 			StartSignalName:    t.Start,
-			EndSignalName:      t.End,
+			EndSignalNames:     t.endSignals(),
 			CorrelationKeyName: t.CorrelationKey,
 			SpanName:           t.SpanName,
+			SpanNameTemplate:   t.SpanNameTemplate,
 			SpanTimeout:        parseTimeout(t.SpanTimeout),
+			SampleRate:         parseSampleRate(t.SampleRate),
+			MergeEndContext:    t.MergeEndContext,
+			DurationMetric:     t.DurationMetric,
 		}
 		cfg.Traces = append(cfg.Traces, tc)
 	}
 
 	// Convert logs
-	if schema.Logs != nil && len(schema.Logs.Whitelist) > 0 {
-		cfg.Logs = &logConfig{
-			WhitelistNames: schema.Logs.Whitelist,
+	if schema.Logs != nil {
+		logs := &logConfig{
+			WhitelistNames:   schema.Logs.Whitelist,
+			BlacklistNames:   schema.Logs.Blacklist,
+			BodyTemplate:     schema.Logs.BodyTemplate,
+			BodyTemplates:    schema.Logs.BodyTemplates,
+			MinFields:        schema.Logs.MinFields,
+			StructuredErrors: schema.Logs.StructuredErrors,
+		}
+		if !logs.isZero() {
+			cfg.Logs = logs
 		}
 	}
 
@@ -306,7 +679,7 @@ func (s *Aperture) buildConfig(schema Schema) (*config, error) {
 			if !ok {
 				return nil, fmt.Errorf("context key %q not registered (referenced in context.logs)", name)
 			}
-			ctxCfg.Logs = append(ctxCfg.Logs, ContextKey{Key: key, Name: name})
+			ctxCfg.Logs = append(ctxCfg.Logs, ContextKey{Key: key, Name: name, logTransform: s.logTransforms[name]})
 		}
 
 		// Build metric context keys
@@ -315,7 +688,7 @@ func (s *Aperture) buildConfig(schema Schema) (*config, error) {
 			if !ok {
 				return nil, fmt.Errorf("context key %q not registered (referenced in context.metrics)", name)
 			}
-			ctxCfg.Metrics = append(ctxCfg.Metrics, ContextKey{Key: key, Name: name})
+			ctxCfg.Metrics = append(ctxCfg.Metrics, ContextKey{Key: key, Name: name, metricTransform: s.metricTransforms[name]})
 		}
 
 		// Build trace context keys
@@ -324,7 +697,7 @@ func (s *Aperture) buildConfig(schema Schema) (*config, error) {
 			if !ok {
 				return nil, fmt.Errorf("context key %q not registered (referenced in context.traces)", name)
 			}
-			ctxCfg.Traces = append(ctxCfg.Traces, ContextKey{Key: key, Name: name})
+			ctxCfg.Traces = append(ctxCfg.Traces, ContextKey{Key: key, Name: name, metricTransform: s.metricTransforms[name]})
 		}
 
 		if len(ctxCfg.Logs) > 0 || len(ctxCfg.Metrics) > 0 || len(ctxCfg.Traces) > 0 {
@@ -351,6 +724,15 @@ func parseMetricType(s string) MetricType {
 	}
 }
 
+// resolveBuckets returns the explicit buckets of m, or its preset's boundaries.
+// Explicit buckets win if both are set.
+func resolveBuckets(m MetricSchema) []float64 {
+	if len(m.Buckets) > 0 {
+		return slices.Clone(m.Buckets)
+	}
+	return slices.Clone(bucketPresets[m.BucketsPreset])
+}
+
 // parseTimeout parses a duration string, returning 5 minutes as default.
 func parseTimeout(s string) time.Duration {
 	if s == "" {
@@ -363,6 +745,51 @@ func parseTimeout(s string) time.Duration {
 	return d
 }
 
+// parseSampleRate returns the trace sample rate, defaulting to 1 (sample everything).
+func parseSampleRate(r float64) float64 {
+	if r == 0 {
+		return 1
+	}
+	return r
+}
+
+// parseStdoutFormat converts a string to StdoutFormat, defaulting to text.
+func parseStdoutFormat(s string) StdoutFormat {
+	if s == string(StdoutFormatJSON) {
+		return StdoutFormatJSON
+	}
+	return StdoutFormatText
+}
+
+// Flush blocks until all events queued for the observer have been processed,
+// including diagnostics raised while processing them, or until ctx expires.
+// Unlike Apply, the observer is left running.
+//
+// Call it before shutting down providers so in-flight events reach their
+// batches. It does not flush the providers themselves:
+//
+//	if err := ap.Flush(ctx); err != nil {
+//	    log.Printf("aperture flush: %v", err)
+//	}
+//	ap.Close()
+//	pvs.Shutdown(ctx)
+func (s *Aperture) Flush(ctx context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.capitanObserver != nil {
+		if err := s.capitanObserver.Drain(ctx); err != nil {
+			return fmt.Errorf("draining observer: %w", err)
+		}
+	}
+	if s.internalObserver != nil {
+		if err := s.internalObserver.Drain(ctx); err != nil {
+			return fmt.Errorf("draining diagnostics: %w", err)
+		}
+	}
+	return nil
+}
+
 // Close stops observing capitan events.
 //
 // Note: This does NOT shutdown the OTEL providers - that is the caller's responsibility.
@@ -377,4 +804,5 @@ func (s *Aperture) Close() {
 	if s.internalObserver != nil {
 		s.internalObserver.Close()
 	}
+	s.needsRebuild = true
 }
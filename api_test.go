@@ -2,12 +2,23 @@ package aperture
 
 import (
 	"context"
+	"errors"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	apertesting "github.com/zoobzio/aperture/testing"
 	"github.com/zoobzio/capitan"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestNew(t *testing.T) {
@@ -290,6 +301,83 @@ func TestApply_MultipleUpdates(t *testing.T) {
 	time.Sleep(50 * time.Millisecond)
 }
 
+func TestApply_UnchangedConfigKeepsObserver(t *testing.T) {
+	cap := capitan.New()
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	schema := Schema{
+		Logs: &LogSchema{Whitelist: []string{"test.signal"}},
+		Metrics: []MetricSchema{
+			{Signal: "order.created", Name: "orders_total", Type: "counter"},
+		},
+	}
+	if err := sh.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	first := sh.capitanObserver
+
+	// Same content, freshly built, as a reloaded file would be
+	again := Schema{
+		Logs: &LogSchema{Whitelist: []string{"test.signal"}},
+		Metrics: []MetricSchema{
+			{Signal: "order.created", Name: "orders_total", Type: "counter"},
+		},
+	}
+	if err := sh.Apply(again); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if sh.capitanObserver != first {
+		t.Error("expected unchanged config to keep the observer")
+	}
+
+	again.Stdout = true
+	if err := sh.Apply(again); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if sh.capitanObserver == first {
+		t.Error("expected changed config to rebuild the observer")
+	}
+}
+
+func TestRegisterContextKeys(t *testing.T) {
+	cap := capitan.New()
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	type ctxKey string
+	sh.RegisterContextKey("tenant_id", ctxKey("tenant_id"))
+	sh.RegisterContextKeys(map[string]any{
+		"user_id":    ctxKey("user_id"),
+		"request_id": ctxKey("request_id"),
+	})
+
+	schema := Schema{
+		Context: &ContextSchema{
+			Logs:    []string{"user_id", "request_id"},
+			Metrics: []string{"tenant_id"},
+		},
+	}
+	if err := sh.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if got := len(sh.config.ContextExtraction.Logs); got != 2 {
+		t.Errorf("expected 2 log context keys, got %d", got)
+	}
+	if got := len(sh.config.ContextExtraction.Metrics); got != 1 {
+		t.Errorf("expected single-key registration to be kept, got %d metric context keys", got)
+	}
+}
+
 func TestRegisterContextKey(t *testing.T) {
 	ctx := context.Background()
 	cap := capitan.New()
@@ -337,6 +425,40 @@ func TestRegisterContextKey(t *testing.T) {
 	}
 }
 
+func TestRegisterContextTransformer(t *testing.T) {
+	cap := capitan.New()
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	type ctxKey string
+	sh.RegisterContextKey("request_meta", ctxKey("request_meta"))
+	sh.RegisterContextTransformer("request_meta", func(any) []log.KeyValue { return nil })
+	sh.RegisterContextMetricTransformer("request_meta", func(any) []attribute.KeyValue { return nil })
+
+	err = sh.Apply(Schema{
+		Context: &ContextSchema{
+			Logs:    []string{"request_meta"},
+			Metrics: []string{"request_meta"},
+			Traces:  []string{"request_meta"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	ce := sh.config.ContextExtraction
+	if ce.Logs[0].logTransform == nil {
+		t.Error("expected log transformer on log context key")
+	}
+	if ce.Metrics[0].metricTransform == nil || ce.Traces[0].metricTransform == nil {
+		t.Error("expected metric transformer on metric and trace context keys")
+	}
+}
+
 func TestApply_UnregisteredContextKey(t *testing.T) {
 	ctx := context.Background()
 	cap := capitan.New()
@@ -371,3 +493,677 @@ func TestApply_UnregisteredContextKey(t *testing.T) {
 		t.Errorf("expected error to mention key name, got: %v", err)
 	}
 }
+
+func TestMetricNames(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	pvs, err := apertesting.TestProviders(ctx, "test-service", "v1.0.0", "localhost:4318")
+	if err != nil {
+		t.Fatalf("failed to create providers: %v", err)
+	}
+	defer pvs.Shutdown(ctx)
+
+	sh, err := New(cap, pvs.Log, pvs.Meter, pvs.Trace)
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	// No configuration - no instruments
+	if names := sh.MetricNames(); len(names) != 0 {
+		t.Errorf("expected no metric names, got %v", names)
+	}
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{Signal: "order.created", Name: "orders_total", Type: "counter"},
+			{Signal: "order.created", Name: "order_value", Type: "histogram", ValueKey: "total"},
+			{Signal: "cpu.usage", Name: "cpu_usage", Type: "unknown_type", ValueKey: "percent"},
+		},
+	}
+
+	err = sh.Apply(schema)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	names := sh.MetricNames()
+	want := []string{"cpu_usage", "order_value", "orders_total"}
+	if len(names) != len(want) {
+		t.Fatalf("MetricNames() = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("MetricNames()[%d] = %q, want %q", i, names[i], want[i])
+		}
+	}
+
+	// Reset clears instruments
+	err = sh.Apply(Schema{})
+	if err != nil {
+		t.Fatalf("Apply(empty) failed: %v", err)
+	}
+	if names := sh.MetricNames(); len(names) != 0 {
+		t.Errorf("expected no metric names after reset, got %v", names)
+	}
+}
+
+func TestWithStaticAttributes(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	logProvider := apertesting.NewMockLoggerProvider()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	recorder := tracetest.NewSpanRecorder()
+	traceProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer traceProvider.Shutdown(ctx)
+
+	sh, err := New(cap, logProvider, meterProvider, traceProvider)
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	sh.WithStaticAttributes(
+		attribute.String("component", "billing"),
+		attribute.String("region", "default"),
+	)
+
+	err = sh.Apply(Schema{
+		Metrics: []MetricSchema{
+			{Signal: "order.created", Name: "orders_total", Type: "counter"},
+		},
+		Traces: []TraceSchema{
+			{Start: "request.started", End: "request.completed", CorrelationKey: "request_id"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	orderCreated := capitan.NewSignal("order.created", "Order Created")
+	requestStarted := capitan.NewSignal("request.started", "Request Started")
+	requestCompleted := capitan.NewSignal("request.completed", "Request Completed")
+	regionKey := capitan.NewStringKey("region")
+	requestIDKey := capitan.NewStringKey("request_id")
+
+	// Event field "region" overrides the static attribute of the same key
+	cap.Emit(ctx, orderCreated, regionKey.Field("eu-west"))
+	cap.Emit(ctx, requestStarted, requestIDKey.Field("req-1"))
+	cap.Emit(ctx, requestCompleted, requestIDKey.Field("req-1"))
+
+	time.Sleep(100 * time.Millisecond)
+
+	// Logs: static attributes merged with event fields
+	var orderLog *log.Record
+	records := logProvider.Capture().Records()
+	for i := range records {
+		if records[i].Body().AsString() == "Order Created" {
+			orderLog = &records[i]
+		}
+	}
+	if orderLog == nil {
+		t.Fatal("expected log record for order.created")
+	}
+	logAttrs := make(map[string]string)
+	orderLog.WalkAttributes(func(kv log.KeyValue) bool {
+		logAttrs[kv.Key] = kv.Value.AsString() // later values win
+		return true
+	})
+	if logAttrs["component"] != "billing" {
+		t.Errorf("log component = %q, want 'billing'", logAttrs["component"])
+	}
+	if logAttrs["region"] != "eu-west" {
+		t.Errorf("log region = %q, want event field 'eu-west'", logAttrs["region"])
+	}
+
+	// Metrics: static attributes merged with event fields
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	var sawCounter bool
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			data, ok := m.Data.(metricdata.Sum[int64])
+			if m.Name != "orders_total" || !ok || len(data.DataPoints) != 1 {
+				continue
+			}
+			sawCounter = true
+			attrs := data.DataPoints[0].Attributes
+			if v, _ := attrs.Value("component"); v.AsString() != "billing" {
+				t.Errorf("metric component = %q, want 'billing'", v.AsString())
+			}
+			if v, _ := attrs.Value("region"); v.AsString() != "eu-west" {
+				t.Errorf("metric region = %q, want event field 'eu-west'", v.AsString())
+			}
+		}
+	}
+	if !sawCounter {
+		t.Error("expected orders_total to be recorded")
+	}
+
+	// Spans: static attributes set at creation
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	spanAttrs := attribute.NewSet(spans[0].Attributes()...)
+	if v, _ := spanAttrs.Value("component"); v.AsString() != "billing" {
+		t.Errorf("span component = %q, want 'billing'", v.AsString())
+	}
+
+	// Clearing removes them from subsequent events
+	sh.WithStaticAttributes()
+	logProvider.Capture().Reset()
+
+	cap.Emit(ctx, orderCreated)
+	time.Sleep(100 * time.Millisecond)
+
+	for _, r := range logProvider.Capture().Records() {
+		r.WalkAttributes(func(kv log.KeyValue) bool {
+			if kv.Key == "component" {
+				t.Error("expected component attribute to be cleared")
+			}
+			return true
+		})
+	}
+}
+
+func TestWithStaticAttributes_PersistsAcrossApply(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	logProvider := apertesting.NewMockLoggerProvider()
+
+	sh, err := New(cap, logProvider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	sh.WithStaticAttributes(attribute.String("component", "search"))
+
+	if err := sh.Apply(Schema{}); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	sig := capitan.NewSignal("query.run", "Query Run")
+	cap.Emit(ctx, sig)
+
+	if !logProvider.Capture().WaitForCount(1, time.Second) {
+		t.Fatal("expected log record")
+	}
+
+	record := logProvider.Capture().Records()[0]
+	if v := getAttributeValue(&record, "component"); v != "search" {
+		t.Errorf("component = %q, want 'search'", v)
+	}
+}
+
+func TestApply_RollbackOnInstrumentError(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(Schema{
+		Metrics: []MetricSchema{
+			{Signal: "order.created", Name: "orders_total", Type: "counter"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	// Passes schema validation but the SDK rejects the instrument name
+	err = sh.Apply(Schema{
+		Metrics: []MetricSchema{
+			{Signal: "order.created", Name: "orders_total", Type: "counter"},
+			{Signal: "order.failed", Name: "1 invalid name", Type: "counter"},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected Apply to fail for an invalid instrument name")
+	}
+
+	// Previous config is still in effect
+	if names := sh.MetricNames(); len(names) != 1 || names[0] != "orders_total" {
+		t.Errorf("MetricNames() = %v, want [orders_total]", names)
+	}
+
+	orderCreated := capitan.NewSignal("order.created", "Order Created")
+	cap.Emit(ctx, orderCreated)
+	cap.Emit(ctx, orderCreated)
+
+	time.Sleep(100 * time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	var total int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if data, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == "orders_total" {
+				for _, dp := range data.DataPoints {
+					total += dp.Value
+				}
+			}
+		}
+	}
+	if total != 2 {
+		t.Errorf("orders_total = %d, want 2 (previous observer still processing)", total)
+	}
+}
+
+func TestApplyAll(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	spanRecorder := tracetest.NewSpanRecorder()
+	traceProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+	defer traceProvider.Shutdown(ctx)
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, traceProvider)
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	metricsSchema := Schema{
+		Metrics: []MetricSchema{
+			{Signal: "order.created", Name: "orders_total", Type: "counter"},
+		},
+	}
+	tracesSchema := Schema{
+		Traces: []TraceSchema{
+			{Start: "request.started", End: "request.completed", CorrelationKey: "request_id", SpanName: "request"},
+		},
+	}
+
+	if err := sh.ApplyAll(metricsSchema, tracesSchema); err != nil {
+		t.Fatalf("ApplyAll failed: %v", err)
+	}
+
+	orderCreated := capitan.NewSignal("order.created", "Order Created")
+	requestStarted := capitan.NewSignal("request.started", "Request Started")
+	requestCompleted := capitan.NewSignal("request.completed", "Request Completed")
+	requestID := capitan.NewStringKey("request_id")
+
+	cap.Emit(ctx, orderCreated)
+	cap.Emit(ctx, requestStarted, requestID.Field("REQ-1"))
+	cap.Emit(ctx, requestCompleted, requestID.Field("REQ-1"))
+
+	time.Sleep(100 * time.Millisecond)
+
+	if names := sh.MetricNames(); len(names) != 1 || names[0] != "orders_total" {
+		t.Errorf("MetricNames() = %v, want [orders_total]", names)
+	}
+
+	spans := spanRecorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "request" {
+		t.Errorf("expected 1 span named request, got %d", len(spans))
+	}
+}
+
+func TestApplyAll_InvalidSchemaKeepsPrevious(t *testing.T) {
+	cap := capitan.New()
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	if err := sh.Apply(Schema{
+		Metrics: []MetricSchema{
+			{Signal: "order.created", Name: "orders_total", Type: "counter"},
+		},
+	}); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		schemas []Schema
+	}{
+		{
+			name: "invalid second schema",
+			schemas: []Schema{
+				{Metrics: []MetricSchema{{Signal: "cpu.usage", Name: "cpu", Type: "gauge", ValueKey: "percent"}}},
+				{Traces: []TraceSchema{{Start: "request.started", End: "request.completed"}}},
+			},
+		},
+		{
+			name: "duplicate names across schemas",
+			schemas: []Schema{
+				{Metrics: []MetricSchema{{Signal: "cpu.usage", Name: "cpu", Type: "gauge", ValueKey: "percent"}}},
+				{Metrics: []MetricSchema{{Signal: "cpu.idle", Name: "cpu", Type: "gauge", ValueKey: "percent"}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := sh.ApplyAll(tt.schemas...); err == nil {
+				t.Fatal("expected ApplyAll to fail")
+			}
+			if names := sh.MetricNames(); len(names) != 1 || names[0] != "orders_total" {
+				t.Errorf("MetricNames() = %v, want [orders_total]", names)
+			}
+		})
+	}
+}
+
+func TestAperture_Validate(t *testing.T) {
+	cap := capitan.New()
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	type ctxKey string
+	sh.RegisterContextKey("user_id", ctxKey("user_id"))
+
+	if err := sh.Apply(Schema{Logs: &LogSchema{Whitelist: []string{"order.created"}}}); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	observer := sh.capitanObserver
+
+	valid := Schema{
+		Metrics: []MetricSchema{{Signal: "order.created", Name: "orders_total"}},
+		Context: &ContextSchema{Logs: []string{"user_id"}},
+	}
+	if err := sh.Validate(valid); err != nil {
+		t.Errorf("Validate(valid) = %v, want nil", err)
+	}
+
+	err = sh.Validate(Schema{Metrics: []MetricSchema{{Signal: "order.created"}}})
+	if err == nil || !strings.Contains(err.Error(), "invalid schema") {
+		t.Errorf("expected invalid schema error, got %v", err)
+	}
+
+	err = sh.Validate(Schema{Context: &ContextSchema{Traces: []string{"tenant_id"}}})
+	if err == nil || !strings.Contains(err.Error(), "not registered") {
+		t.Errorf("expected unregistered context key error, got %v", err)
+	}
+
+	// No side effects on the running configuration
+	if sh.capitanObserver != observer {
+		t.Error("Validate replaced the observer")
+	}
+	if sh.config.Logs == nil || sh.config.Logs.WhitelistNames[0] != "order.created" {
+		t.Error("Validate changed the running config")
+	}
+	if names := sh.MetricNames(); len(names) != 0 {
+		t.Errorf("Validate created metrics: %v", names)
+	}
+}
+
+func TestFlush(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	logProvider := apertesting.NewMockLoggerProvider()
+	capture := logProvider.Capture()
+
+	sh, err := New(cap, logProvider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	orderCreated := capitan.NewSignal("order.created", "Order Created")
+	for range 5 {
+		cap.Emit(ctx, orderCreated)
+	}
+
+	if err := sh.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if got := len(capture.Records()); got != 5 {
+		t.Errorf("expected 5 records after Flush, got %d", got)
+	}
+
+	// The observer keeps running
+	cap.Emit(ctx, orderCreated)
+	if err := sh.Flush(ctx); err != nil {
+		t.Fatalf("second Flush failed: %v", err)
+	}
+	if got := len(capture.Records()); got != 6 {
+		t.Errorf("expected 6 records after second Flush, got %d", got)
+	}
+}
+
+func TestReset(t *testing.T) {
+	cap := capitan.New()
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(Schema{
+		Metrics: []MetricSchema{
+			{Signal: "order.created", Name: "orders_total", Type: "counter"},
+		},
+		Traces: []TraceSchema{
+			{Start: "job.started", End: "job.done", CorrelationKey: "job_id"},
+		},
+		Logs: &LogSchema{Whitelist: []string{"order.created"}},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if sh.capitanObserver.metricsHandler == nil || sh.capitanObserver.tracesHandler == nil {
+		t.Fatal("expected metrics and traces handlers after Apply")
+	}
+
+	if err := sh.Reset(); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+
+	if sh.capitanObserver.metricsHandler != nil {
+		t.Error("expected metrics handler to be nil after Reset")
+	}
+	if sh.capitanObserver.tracesHandler != nil {
+		t.Error("expected traces handler to be nil after Reset")
+	}
+	if sh.config.Logs != nil {
+		t.Error("expected Logs config to be nil after Reset")
+	}
+	if !sh.capitanObserver.logFilter.Load().allows("anything") {
+		t.Error("expected all signals to be logged after Reset")
+	}
+}
+
+func TestApplyWithContext(t *testing.T) {
+	cap := capitan.New()
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err = sh.ApplyWithContext(ctx, Schema{
+		Metrics: []MetricSchema{
+			{Signal: "order.created", Name: "orders_total", Type: "counter"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ApplyWithContext failed: %v", err)
+	}
+	if names := sh.MetricNames(); len(names) != 1 || names[0] != "orders_total" {
+		t.Errorf("MetricNames() = %v, want [orders_total]", names)
+	}
+}
+
+func TestApplyWithContext_Expired(t *testing.T) {
+	cap := capitan.New()
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	if err := sh.Apply(Schema{
+		Metrics: []MetricSchema{
+			{Signal: "order.created", Name: "orders_total", Type: "counter"},
+		},
+	}); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = sh.ApplyWithContext(ctx, Schema{
+		Metrics: []MetricSchema{
+			{Signal: "cpu.usage", Name: "cpu", Type: "gauge", ValueKey: "percent"},
+		},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	// Previous config is still in effect
+	if names := sh.MetricNames(); len(names) != 1 || names[0] != "orders_total" {
+		t.Errorf("MetricNames() = %v, want [orders_total]", names)
+	}
+}
+
+// scopedLoggerProvider hands out a separate mock logger per scope name.
+type scopedLoggerProvider struct {
+	embedded.LoggerProvider
+	mu      sync.Mutex
+	loggers map[string]*mockLogger
+}
+
+func (p *scopedLoggerProvider) Logger(name string, _ ...log.LoggerOption) log.Logger {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.loggers == nil {
+		p.loggers = make(map[string]*mockLogger)
+	}
+	if _, ok := p.loggers[name]; !ok {
+		p.loggers[name] = newMockLogger()
+	}
+	return p.loggers[name]
+}
+
+func (p *scopedLoggerProvider) records(name string) []log.Record {
+	p.mu.Lock()
+	logger, ok := p.loggers[name]
+	p.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return logger.getRecords()
+}
+
+func TestApply_ScopeName(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	logProvider := &scopedLoggerProvider{}
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+	recorder := tracetest.NewSpanRecorder()
+	traceProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer traceProvider.Shutdown(ctx)
+
+	sh, err := New(cap, logProvider, meterProvider, traceProvider)
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(Schema{
+		ScopeName:         "billing",
+		PendingSpanMetric: true,
+		Metrics:           []MetricSchema{{Signal: "invoice.paid", Name: "invoices_paid_total"}},
+		Traces:            []TraceSchema{{Start: "invoice.started", End: "invoice.paid", CorrelationKey: "invoice_id"}},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	started := capitan.NewSignal("invoice.started", "Invoice started")
+	paid := capitan.NewSignal("invoice.paid", "Invoice paid")
+	invoiceID := capitan.NewStringKey("invoice_id")
+
+	cap.Emit(ctx, started, invoiceID.Field("INV-1"))
+	cap.Emit(ctx, paid, invoiceID.Field("INV-1"))
+	cap.Emit(ctx, started) // missing correlation key: diagnostic
+	sh.Flush(ctx)
+
+	if got := len(logProvider.records("billing")); got != 3 {
+		t.Errorf("expected 3 event records on billing scope, got %d", got)
+	}
+	if findRecordWithSignal(logProvider.records("billing.internal"), SignalTraceCorrelationMissing.Name()) == nil {
+		t.Error("expected diagnostic on billing.internal scope")
+	}
+	if got := len(logProvider.records("capitan")); got != 0 {
+		t.Errorf("expected no records on default scope, got %d", got)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("failed to collect metrics: %v", err)
+	}
+	scopes := make(map[string]string)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			scopes[m.Name] = sm.Scope.Name
+		}
+	}
+	if scopes["invoices_paid_total"] != "billing" {
+		t.Errorf("invoices_paid_total scope = %q, want billing", scopes["invoices_paid_total"])
+	}
+	if scopes[pendingSpanMetricName] != "billing.internal" {
+		t.Errorf("%s scope = %q, want billing.internal", pendingSpanMetricName, scopes[pendingSpanMetricName])
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].InstrumentationScope().Name != "billing" {
+		t.Fatalf("expected 1 span on billing scope, got %d", len(spans))
+	}
+
+	// Reset restores the default scopes
+	if err := sh.Reset(); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	cap.Emit(ctx, paid)
+	sh.Flush(ctx)
+	if got := len(logProvider.records("capitan")); got != 1 {
+		t.Errorf("expected 1 record on default scope after reset, got %d", got)
+	}
+}
@@ -0,0 +1,95 @@
+package aperture
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/log"
+)
+
+// bodyTemplate renders a log record body from event fields.
+//
+// Templates use {field_name} placeholders; {{ and }} produce literal braces.
+// It is immutable once parsed.
+type bodyTemplate struct {
+	segments []bodySegment
+}
+
+// bodySegment is either literal text or a field placeholder.
+type bodySegment struct {
+	text  string // literal text (field is empty)
+	field string // placeholder field key name
+}
+
+// parseBodyTemplate compiles tmpl into segments.
+// Returns an error for unterminated or empty placeholders.
+func parseBodyTemplate(tmpl string) (*bodyTemplate, error) {
+	bt := &bodyTemplate{}
+	var literal strings.Builder
+
+	flush := func() {
+		if literal.Len() > 0 {
+			bt.segments = append(bt.segments, bodySegment{text: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(tmpl); i++ {
+		c := tmpl[i]
+		switch {
+		case c == '{' && strings.HasPrefix(tmpl[i:], "{{"):
+			literal.WriteByte('{')
+			i++
+		case c == '}' && strings.HasPrefix(tmpl[i:], "}}"):
+			literal.WriteByte('}')
+			i++
+		case c == '{':
+			end := strings.IndexByte(tmpl[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated placeholder at offset %d", i)
+			}
+			name := strings.TrimSpace(tmpl[i+1 : i+end])
+			if name == "" {
+				return nil, fmt.Errorf("empty placeholder at offset %d", i)
+			}
+			flush()
+			bt.segments = append(bt.segments, bodySegment{field: name})
+			i += end
+		default:
+			literal.WriteByte(c)
+		}
+	}
+	flush()
+
+	return bt, nil
+}
+
+// render substitutes placeholders with the matching attribute values.
+// Returns false if any placeholder has no matching attribute, so the caller
+// can fall back to the signal description.
+func (bt *bodyTemplate) render(attrs []log.KeyValue) (string, bool) {
+	var b strings.Builder
+	for _, seg := range bt.segments {
+		if seg.field == "" {
+			b.WriteString(seg.text)
+			continue
+		}
+
+		value, ok := findLogAttribute(attrs, seg.field)
+		if !ok {
+			return "", false
+		}
+		b.WriteString(value.String())
+	}
+	return b.String(), true
+}
+
+// findLogAttribute returns the value of the first attribute with the given key.
+func findLogAttribute(attrs []log.KeyValue, key string) (log.Value, bool) {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return log.Value{}, false
+}
@@ -0,0 +1,104 @@
+package aperture
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/otel/log"
+)
+
+func TestParseBodyTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    string
+		want    []bodySegment
+		wantErr bool
+	}{
+		{
+			name: "literal only",
+			tmpl: "order created",
+			want: []bodySegment{{text: "order created"}},
+		},
+		{
+			name: "placeholders",
+			tmpl: "order {order_id} created by {user}",
+			want: []bodySegment{
+				{text: "order "},
+				{field: "order_id"},
+				{text: " created by "},
+				{field: "user"},
+			},
+		},
+		{
+			name: "escaped braces",
+			tmpl: "{{literal}} {id}",
+			want: []bodySegment{
+				{text: "{literal} "},
+				{field: "id"},
+			},
+		},
+		{
+			name: "whitespace around name",
+			tmpl: "{ id }",
+			want: []bodySegment{{field: "id"}},
+		},
+		{
+			name:    "unterminated placeholder",
+			tmpl:    "order {order_id created",
+			wantErr: true,
+		},
+		{
+			name:    "empty placeholder",
+			tmpl:    "order {} created",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bt, err := parseBodyTemplate(tt.tmpl)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(bt.segments) != len(tt.want) {
+				t.Fatalf("segments = %+v, want %+v", bt.segments, tt.want)
+			}
+			for i := range tt.want {
+				if bt.segments[i] != tt.want[i] {
+					t.Errorf("segments[%d] = %+v, want %+v", i, bt.segments[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBodyTemplateRender(t *testing.T) {
+	bt, err := parseBodyTemplate("order {order_id} total {total} paid={paid}")
+	if err != nil {
+		t.Fatalf("parseBodyTemplate failed: %v", err)
+	}
+
+	attrs := []log.KeyValue{
+		log.String("order_id", "ORD-1"),
+		log.Float64("total", 99.5),
+		log.Bool("paid", true),
+	}
+
+	got, ok := bt.render(attrs)
+	if !ok {
+		t.Fatal("expected render to succeed")
+	}
+	if want := "order ORD-1 total 99.5 paid=true"; got != want {
+		t.Errorf("render = %q, want %q", got, want)
+	}
+
+	// A missing field fails the render so the caller can fall back
+	if _, ok := bt.render(attrs[:1]); ok {
+		t.Error("expected render to fail when a referenced field is missing")
+	}
+}
@@ -2,44 +2,109 @@ package aperture
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
 
 	"github.com/zoobzio/capitan"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/metric"
 )
 
+// logsEmittedMetricName is the counter of log records forwarded per signal.
+const logsEmittedMetricName = "aperture_logs_emitted_total"
+
 // capitanObserver observes all capitan events and transforms them to OTEL signals.
 type capitanObserver struct {
-	logger         log.Logger          // interface (16 bytes) - pointers first
-	observer       *capitan.Observer   // pointers (8 bytes each)
-	metricsHandler *metricsHandler
-	tracesHandler  *tracesHandler
-	logWhitelist   map[string]struct{} // signal name → allowed
-	stdoutLogger   *stdoutLogger
-	internal       *internalObserver
-	logContextKeys []ContextKey // slice last (pointer in first 8 bytes)
+	logger             log.Logger        // interface (16 bytes) - pointers first
+	observer           *capitan.Observer // pointers (8 bytes each)
+	metricsHandler     *metricsHandler
+	tracesHandler      *tracesHandler
+	logFilter          atomic.Pointer[logFilter]        // swapped in place by SetLogFilter
+	staticAttrs        atomic.Pointer[staticAttributes] // swapped in place by WithStaticAttributes
+	stdoutLogger       *stdoutLogger
+	bodyTemplate       *bodyTemplate            // nil = signal description
+	signalTemplates    map[string]*bodyTemplate // per-signal body templates, override bodyTemplate
+	excludeFields      map[string]struct{}      // field keys dropped before attribute conversion
+	logsEmitted        metric.Int64Counter      // nil unless logs_emitted_metric is set
+	internal           *internalObserver
+	unused             *unusedTracker
+	logContextKeys     []ContextKey // slice last (pointer in first 8 bytes)
+	logMinFields       int          // skip logging events with fewer fields
+	maxAttributeBytes  int          // truncate longer string and bytes values, 0 = no limit
+	strictWhitelist    bool         // gate metrics and traces by the live log whitelist
+	structuredErrors   bool         // add type and code attributes for error fields
+	keepUnserializable bool         // placeholder for custom fields that fail JSON serialization
+	sortAttributes     bool         // emit log attributes sorted by key
+	mirrorMeta         bool         // copy severity and timestamp into log attributes
 }
 
 // newCapitanObserver creates and attaches an observer to the capitan instance.
 //
 // Events are transformed to OTEL signals based on configuration.
 func newCapitanObserver(s *Aperture, c *capitan.Capitan) (*capitanObserver, error) {
-	// Create metrics handler if configured
-	metricsHandler, err := newMetricsHandler(s)
+	co, err := buildCapitanObserver(s)
 	if err != nil {
 		return nil, err
 	}
+	co.attach(c)
+	return co, nil
+}
+
+// buildCapitanObserver creates an observer from the current config without
+// attaching it, so Apply can fail before touching the live observer.
+func buildCapitanObserver(s *Aperture) (*capitanObserver, error) {
+	logs := s.config.logs()
 
-	// Build log whitelist if configured (now uses signal names)
-	var logWhitelist map[string]struct{}
-	if s.config.Logs != nil && len(s.config.Logs.WhitelistNames) > 0 {
-		logWhitelist = make(map[string]struct{})
-		for _, name := range s.config.Logs.WhitelistNames {
-			logWhitelist[name] = struct{}{}
+	// Compile log body templates if configured (syntax checked by Validate)
+	signalTemplates := make(map[string]*bodyTemplate, len(logs.BodyTemplates))
+	for signal, tmpl := range logs.BodyTemplates {
+		bt, err := parseBodyTemplate(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("log body template for %s: %w", signal, err)
+		}
+		signalTemplates[signal] = bt
+	}
+	var bodyTemplate *bodyTemplate
+	if logs.BodyTemplate != "" {
+		var err error
+		bodyTemplate, err = parseBodyTemplate(logs.BodyTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("log body template: %w", err)
 		}
 	}
 
+	// Create the log volume counter if enabled
+	var logsEmitted metric.Int64Counter
+	if s.config.LogsEmittedMetric {
+		var err error
+		logsEmitted, err = s.meterProvider.Meter(s.config.internalMeterName()).Int64Counter(
+			logsEmittedMetricName,
+			metric.WithDescription("Log records forwarded to the log provider"),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("creating logs emitted metric: %w", err)
+		}
+	}
+
+	// Create metrics handler if configured
+	metricsHandler, err := newMetricsHandler(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build log filter if configured (matches by signal name)
+	filter := newLogFilter(logs.WhitelistNames, logs.BlacklistNames)
+
 	// Create traces handler if configured
-	tracesHandler := newTracesHandler(s)
+	tracesHandler, err := newTracesHandler(s)
+	if err != nil {
+		metricsHandler.Close()
+		return nil, err
+	}
 
 	// Extract context keys if configured
 	var logContextKeys []ContextKey
@@ -47,28 +112,45 @@ func newCapitanObserver(s *Aperture, c *capitan.Capitan) (*capitanObserver, erro
 		logContextKeys = s.config.ContextExtraction.Logs
 	}
 
+	excludeFields := fieldKeySet(s.config.ExcludeFields)
+
 	// Create stdout logger if enabled
 	var stdoutLogger *stdoutLogger
 	if s.config.StdoutLogging {
-		stdoutLogger = newStdoutLogger()
+		stdoutLogger = newStdoutLogger(s.config.StdoutFormat, logs.StructuredErrors, excludeFields)
 	}
 
 	co := &capitanObserver{
-		logger:         s.logProvider.Logger("capitan"),
-		metricsHandler: metricsHandler,
-		tracesHandler:  tracesHandler,
-		logWhitelist:   logWhitelist,
-		logContextKeys: logContextKeys,
-		stdoutLogger:   stdoutLogger,
-		internal:       s.internalObserver,
+		logger:             s.logProvider.Logger(s.config.scopeName()),
+		metricsHandler:     metricsHandler,
+		tracesHandler:      tracesHandler,
+		logContextKeys:     logContextKeys,
+		stdoutLogger:       stdoutLogger,
+		bodyTemplate:       bodyTemplate,
+		signalTemplates:    signalTemplates,
+		logsEmitted:        logsEmitted,
+		internal:           s.internalObserver,
+		unused:             newUnusedTracker(s.config, s.internalObserver),
+		logMinFields:       logs.MinFields,
+		maxAttributeBytes:  s.config.MaxAttributeBytes,
+		excludeFields:      excludeFields,
+		strictWhitelist:    s.config.StrictWhitelist,
+		structuredErrors:   logs.StructuredErrors,
+		keepUnserializable: s.config.KeepUnserializable,
+		sortAttributes:     s.config.SortAttributes,
+		mirrorMeta:         s.config.MirrorMetaAttributes,
 	}
-
-	// Observe all signals
-	co.observer = c.Observe(co.handleEvent)
+	co.logFilter.Store(filter)
+	co.staticAttrs.Store(newStaticAttributes(s.staticAttrs))
 
 	return co, nil
 }
 
+// attach starts observing all signals on the capitan instance.
+func (co *capitanObserver) attach(c *capitan.Capitan) {
+	co.observer = c.Observe(co.handleEvent)
+}
+
 // handleEvent transforms a capitan event to OTEL signals based on configuration.
 func (co *capitanObserver) handleEvent(ctx context.Context, e *capitan.Event) {
 	// Log to stdout if enabled (before any filtering)
@@ -76,52 +158,309 @@ func (co *capitanObserver) handleEvent(ctx context.Context, e *capitan.Event) {
 		co.stdoutLogger.logEvent(ctx, e, co.logContextKeys)
 	}
 
+	co.unused.markSeen(e.Signal().Name())
+
+	filter := co.logFilter.Load()
+	logged := filter.allows(e.Signal().Name()) && len(e.Fields()) >= co.logMinFields
+	static := co.staticAttrs.Load()
+
+	// In strict mode, metrics and traces only see whitelisted signals
+	vetted := !co.strictWhitelist || filter.whitelisted(e.Signal().Name())
+
+	// Report unsigned values that will be clamped, and custom values that
+	// cannot be serialized, by log or metric transformation
+	if logged || (vetted && co.metricsHandler.handles(e.Signal().Name())) {
+		co.reportClampedFields(ctx, e)
+		co.reportUnserializableFields(ctx, e)
+	}
+
 	// Handle metrics if configured
-	if co.metricsHandler != nil {
-		co.metricsHandler.handleEvent(ctx, e, co.internal)
+	if co.metricsHandler != nil && vetted {
+		co.metricsHandler.handleEvent(ctx, e, static.metricAttrs(), co.internal)
 	}
 
 	// Handle traces if configured
-	if co.tracesHandler != nil {
-		co.tracesHandler.handleEvent(ctx, e)
+	if co.tracesHandler != nil && vetted {
+		co.tracesHandler.handleEvent(ctx, e, static.metricAttrs())
 	}
 
-	// Handle logs with whitelist filtering (now matches by signal name)
-	if co.logWhitelist != nil {
-		// Whitelist configured - only log if signal name is in whitelist
-		if _, ok := co.logWhitelist[e.Signal().Name()]; !ok {
-			return
-		}
+	// Handle logs with whitelist/blacklist and min-fields filtering
+	if !logged {
+		return
 	}
 
 	// Build log record
 	var record log.Record
 
-	// Set timestamp from event
+	// Set timestamp from event; the observed timestamp records when aperture
+	// processed it, so pipelines can measure delivery lag
 	record.SetTimestamp(e.Timestamp())
+	record.SetObservedTimestamp(time.Now())
 
 	// Map capitan severity to OTEL severity
 	record.SetSeverity(severityToOTEL(e.Severity()))
 	record.SetSeverityText(string(e.Severity()))
 
-	// Set message from signal description
-	record.SetBody(log.StringValue(e.Signal().Description()))
+	// Transform all fields (no transformers - use JSON fallback)
+	result := fieldsToAttributes(withoutFields(e.Fields(), co.excludeFields), co.structuredErrors, co.keepUnserializable, co.maxAttributeBytes)
+
+	// Set message from body template, or signal description
+	record.SetBody(log.StringValue(co.logBody(e, result.attrs)))
 
-	// Add signal as attribute
-	record.AddAttributes(log.String("capitan.signal", e.Signal().Name()))
+	// Signal name and description as attributes for filtering.
+	// capitan.signal is kept for existing queries.
+	signalAttrs := []log.KeyValue{
+		log.String("capitan.signal", e.Signal().Name()),
+		log.String("aperture.signal", e.Signal().Name()),
+		log.String("aperture.signal.description", e.Signal().Description()),
+	}
 
-	// Transform and add all fields (no transformers - use JSON fallback)
-	result := fieldsToAttributes(e.Fields())
-	record.AddAttributes(result.attrs...)
+	// Mirror severity and timestamp for backends that don't index the native fields
+	if co.mirrorMeta {
+		signalAttrs = append(signalAttrs,
+			log.String("event.severity", string(e.Severity())),
+			log.String("event.timestamp", e.Timestamp().Format(time.RFC3339Nano)),
+		)
+	}
 
-	// Extract and add context values if configured
+	// Extract context values if configured
+	var contextAttrs []log.KeyValue
 	if len(co.logContextKeys) > 0 {
-		contextAttrs := extractContextValuesForLogs(ctx, co.logContextKeys)
+		contextAttrs = extractContextValuesForLogs(ctx, co.logContextKeys)
+	}
+
+	// Instance-wide static attributes go before fields so fields take precedence
+	if co.sortAttributes {
+		attrs := slices.Concat(signalAttrs, static.logAttrs(), result.attrs, contextAttrs)
+		sortLogAttributes(attrs)
+		record.AddAttributes(attrs...)
+	} else {
+		record.AddAttributes(signalAttrs...)
+		record.AddAttributes(static.logAttrs()...)
+		record.AddAttributes(result.attrs...)
 		record.AddAttributes(contextAttrs...)
 	}
 
 	// Emit log record
 	co.logger.Emit(ctx, record)
+
+	if co.logsEmitted != nil {
+		co.logsEmitted.Add(ctx, 1, metric.WithAttributes(attribute.String("signal", e.Signal().Name())))
+	}
+}
+
+// logBody returns the rendered body template, or the signal description if no
+// template is configured or the event lacks a referenced field. A signal's own
+// template takes precedence over the global one.
+func (co *capitanObserver) logBody(e *capitan.Event, attrs []log.KeyValue) string {
+	tmpl := co.bodyTemplate
+	if bt, ok := co.signalTemplates[e.Signal().Name()]; ok {
+		tmpl = bt
+	}
+	if tmpl != nil {
+		if body, ok := tmpl.render(attrs); ok {
+			return body
+		}
+	}
+	return e.Signal().Description()
+}
+
+// reportClampedFields emits a diagnostic for each field whose value exceeds math.MaxInt64.
+func (co *capitanObserver) reportClampedFields(ctx context.Context, e *capitan.Event) {
+	for _, c := range findClampedFields(e.Fields()) {
+		co.internal.emit(ctx, SignalValueClamped,
+			internalSignal.Field(e.Signal().Name()),
+			internalFieldKey.Field(c.key),
+			internalOriginalValue.Field(strconv.FormatUint(c.original, 10)),
+		)
+	}
+}
+
+// reportUnserializableFields emits a diagnostic for each custom-typed field
+// whose value fails JSON serialization.
+func (co *capitanObserver) reportUnserializableFields(ctx context.Context, e *capitan.Event) {
+	for _, u := range findUnserializableFields(e.Fields()) {
+		co.internal.emit(ctx, SignalTransformFailed,
+			internalSignal.Field(e.Signal().Name()),
+			internalFieldKey.Field(u.key),
+			internalReason.Field(u.reason),
+		)
+	}
+}
+
+// logFilter decides which signals are forwarded to the OTEL logger.
+// It is immutable once built; changes are made by swapping in a new filter.
+type logFilter struct {
+	whitelist map[string]struct{} // signal name → allowed (nil = all allowed)
+	blacklist map[string]struct{} // signal name → denied
+}
+
+// newLogFilter compiles whitelist and blacklist signal names into a filter.
+// Returns nil if neither list has entries (log everything).
+func newLogFilter(whitelist, blacklist []string) *logFilter {
+	if len(whitelist) == 0 && len(blacklist) == 0 {
+		return nil
+	}
+
+	f := &logFilter{}
+	if len(whitelist) > 0 {
+		f.whitelist = make(map[string]struct{}, len(whitelist))
+		for _, name := range whitelist {
+			f.whitelist[name] = struct{}{}
+		}
+	}
+	if len(blacklist) > 0 {
+		f.blacklist = make(map[string]struct{}, len(blacklist))
+		for _, name := range blacklist {
+			f.blacklist[name] = struct{}{}
+		}
+	}
+
+	return f
+}
+
+// allows reports whether a signal should be logged.
+// A nil filter allows everything. The blacklist takes precedence over the whitelist.
+func (f *logFilter) allows(signalName string) bool {
+	if f == nil {
+		return true
+	}
+	if _, denied := f.blacklist[signalName]; denied {
+		return false
+	}
+	if f.whitelist != nil {
+		_, ok := f.whitelist[signalName]
+		return ok
+	}
+	return true
+}
+
+// whitelisted reports whether a signal is on the whitelist.
+// A nil filter or an empty whitelist allows everything; the blacklist is ignored.
+func (f *logFilter) whitelisted(signalName string) bool {
+	if f == nil || f.whitelist == nil {
+		return true
+	}
+	_, ok := f.whitelist[signalName]
+	return ok
+}
+
+// staticAttributes holds the instance-wide attributes set by WithStaticAttributes,
+// converted once for both the log and metric/trace APIs.
+// It is immutable once built; changes are made by swapping in a new value.
+type staticAttributes struct {
+	metric []attribute.KeyValue
+	log    []log.KeyValue
+}
+
+// newStaticAttributes converts attrs for use by all handlers.
+// Returns nil if attrs is empty.
+func newStaticAttributes(attrs []attribute.KeyValue) *staticAttributes {
+	if len(attrs) == 0 {
+		return nil
+	}
+	return &staticAttributes{
+		metric: attrs,
+		log:    attributesToLog(attrs),
+	}
+}
+
+// metricAttrs returns the attributes for metrics and spans. Safe on a nil receiver.
+func (sa *staticAttributes) metricAttrs() []attribute.KeyValue {
+	if sa == nil {
+		return nil
+	}
+	return sa.metric
+}
+
+// logAttrs returns the attributes for log records. Safe on a nil receiver.
+func (sa *staticAttributes) logAttrs() []log.KeyValue {
+	if sa == nil {
+		return nil
+	}
+	return sa.log
+}
+
+// unusedTracker reports metric and trace configs whose signal is never seen
+// within a warmup window, which usually means a typo in the schema.
+type unusedTracker struct {
+	seen     map[string]*atomic.Bool // signal name → seen; keys fixed at creation
+	timer    *time.Timer
+	internal *internalObserver
+	config   config
+}
+
+// newUnusedTracker starts the warmup timer for cfg.
+// Returns nil if the report is disabled or nothing is configured.
+func newUnusedTracker(cfg config, internal *internalObserver) *unusedTracker {
+	if cfg.UnusedConfigWarmup <= 0 || (len(cfg.Metrics) == 0 && len(cfg.Traces) == 0) {
+		return nil
+	}
+
+	ut := &unusedTracker{
+		seen:     make(map[string]*atomic.Bool),
+		internal: internal,
+		config:   cfg,
+	}
+	for _, mc := range cfg.Metrics {
+		ut.seen[mc.SignalName] = &atomic.Bool{}
+	}
+	for _, tc := range cfg.Traces {
+		ut.seen[tc.StartSignalName] = &atomic.Bool{}
+		for _, name := range tc.EndSignalNames {
+			ut.seen[name] = &atomic.Bool{}
+		}
+	}
+
+	ut.timer = time.AfterFunc(cfg.UnusedConfigWarmup, ut.report)
+	return ut
+}
+
+// markSeen records that a signal was observed. Safe on a nil receiver.
+func (ut *unusedTracker) markSeen(signalName string) {
+	if ut == nil {
+		return
+	}
+	if seen, ok := ut.seen[signalName]; ok && !seen.Load() {
+		seen.Store(true)
+	}
+}
+
+// report emits SignalConfigUnused for each config referencing an unseen signal.
+func (ut *unusedTracker) report() {
+	ctx := context.Background()
+
+	for _, mc := range ut.config.Metrics {
+		if !ut.seen[mc.SignalName].Load() {
+			ut.internal.emit(ctx, SignalConfigUnused,
+				internalSignal.Field(mc.SignalName),
+				internalMetricName.Field(mc.Name),
+			)
+		}
+	}
+
+	for _, tc := range ut.config.Traces {
+		spanName := tc.SpanName
+		if spanName == "" {
+			spanName = tc.StartSignalName
+		}
+		for _, name := range append([]string{tc.StartSignalName}, tc.EndSignalNames...) {
+			if !ut.seen[name].Load() {
+				ut.internal.emit(ctx, SignalConfigUnused,
+					internalSignal.Field(name),
+					internalSpanName.Field(spanName),
+				)
+			}
+		}
+	}
+}
+
+// stop cancels a pending report. Safe on a nil receiver.
+func (ut *unusedTracker) stop() {
+	if ut == nil {
+		return
+	}
+	ut.timer.Stop()
 }
 
 // severityToOTEL maps capitan severity to OTEL log severity.
@@ -156,4 +495,8 @@ func (co *capitanObserver) Close() {
 	if co.tracesHandler != nil {
 		co.tracesHandler.Close()
 	}
+	if co.metricsHandler != nil {
+		co.metricsHandler.Close()
+	}
+	co.unused.stop()
 }
@@ -2,11 +2,19 @@ package aperture
 
 import (
 	"context"
+	"slices"
 	"testing"
+	"time"
 
 	apertesting "github.com/zoobzio/aperture/testing"
 	"github.com/zoobzio/capitan"
 	"go.opentelemetry.io/otel/log"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestSeverityToOTEL(t *testing.T) {
@@ -238,3 +246,763 @@ func TestCapitanObserver_SeverityPropagation(t *testing.T) {
 
 	// Severity mapping is tested directly in TestSeverityToOTEL
 }
+
+func TestCapitanObserver_ObservedTimestamp(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	logProvider := apertesting.NewMockLoggerProvider()
+	capture := logProvider.Capture()
+
+	sh, err := New(cap, logProvider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	testSignal := capitan.NewSignal("test.signal", "Test signal")
+
+	before := time.Now()
+	cap.Emit(ctx, testSignal)
+
+	if !capture.WaitForCount(1, 2*time.Second) {
+		t.Fatal("timed out waiting for log record")
+	}
+	record := capture.Records()[0]
+
+	if record.Timestamp().IsZero() {
+		t.Error("expected event timestamp to be set")
+	}
+	if record.ObservedTimestamp().IsZero() {
+		t.Error("expected observed timestamp to be set")
+	}
+	if record.Timestamp().Before(before) {
+		t.Errorf("event timestamp %v precedes emission at %v", record.Timestamp(), before)
+	}
+	if record.ObservedTimestamp().Before(record.Timestamp()) {
+		t.Errorf("observed timestamp %v precedes event timestamp %v", record.ObservedTimestamp(), record.Timestamp())
+	}
+}
+
+func TestCapitanObserver_BodyTemplate(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	logProvider := apertesting.NewMockLoggerProvider()
+	capture := logProvider.Capture()
+
+	sh, err := New(cap, logProvider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(Schema{
+		Logs: &LogSchema{BodyTemplate: "order {order_id} created ({total})"},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	orderCreated := capitan.NewSignal("order.created", "Order created")
+	orderID := capitan.NewStringKey("order_id")
+	total := capitan.NewInt64Key("total")
+
+	cap.Emit(ctx, orderCreated, orderID.Field("ORD-1"), total.Field(42))
+	cap.Emit(ctx, orderCreated, orderID.Field("ORD-2")) // missing total
+
+	if !capture.WaitForCount(2, 2*time.Second) {
+		t.Fatal("timed out waiting for log records")
+	}
+
+	bodies := make(map[string]bool)
+	for _, r := range capture.Records() {
+		bodies[r.Body().AsString()] = true
+	}
+	if !bodies["order ORD-1 created (42)"] {
+		t.Errorf("expected rendered body, got %v", bodies)
+	}
+	if !bodies["Order created"] {
+		t.Errorf("expected description fallback for missing field, got %v", bodies)
+	}
+}
+
+func TestCapitanObserver_SignalBodyTemplates(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	logProvider := apertesting.NewMockLoggerProvider()
+	capture := logProvider.Capture()
+
+	sh, err := New(cap, logProvider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(Schema{
+		Logs: &LogSchema{
+			BodyTemplate: "event {id}",
+			BodyTemplates: map[string]string{
+				"order.created": "order {id} created",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	orderCreated := capitan.NewSignal("order.created", "Order created")
+	orderShipped := capitan.NewSignal("order.shipped", "Order shipped")
+	id := capitan.NewStringKey("id")
+
+	cap.Emit(ctx, orderCreated, id.Field("ORD-1"))
+	cap.Emit(ctx, orderShipped, id.Field("ORD-2")) // global template
+	cap.Emit(ctx, orderCreated)                    // missing field
+
+	if !capture.WaitForCount(3, 2*time.Second) {
+		t.Fatal("timed out waiting for log records")
+	}
+
+	var bodies []string
+	for _, r := range capture.Records() {
+		bodies = append(bodies, r.Body().AsString())
+	}
+	for _, want := range []string{"order ORD-1 created", "event ORD-2", "Order created"} {
+		if !slices.Contains(bodies, want) {
+			t.Errorf("expected body %q, got %v", want, bodies)
+		}
+	}
+}
+
+func TestCapitanObserver_SignalAttributes(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	logProvider := apertesting.NewMockLoggerProvider()
+	capture := logProvider.Capture()
+
+	sh, err := New(cap, logProvider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	orderCreated := capitan.NewSignal("order.created", "Order created")
+	cap.Emit(ctx, orderCreated)
+
+	if !capture.WaitForCount(1, 2*time.Second) {
+		t.Fatal("timed out waiting for log record")
+	}
+	record := capture.Records()[0]
+
+	attrs := make(map[string]string)
+	record.WalkAttributes(func(kv log.KeyValue) bool {
+		attrs[kv.Key] = kv.Value.AsString()
+		return true
+	})
+
+	want := map[string]string{
+		"capitan.signal":              "order.created",
+		"aperture.signal":             "order.created",
+		"aperture.signal.description": "Order created",
+	}
+	for key, value := range want {
+		if attrs[key] != value {
+			t.Errorf("%s = %q, want %q", key, attrs[key], value)
+		}
+	}
+}
+
+func TestCapitanObserver_SortAttributes(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	logProvider := apertesting.NewMockLoggerProvider()
+	capture := logProvider.Capture()
+
+	sh, err := New(cap, logProvider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	if err := sh.Apply(Schema{SortAttributes: true}); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	sig := capitan.NewSignal("order.sorted", "Order sorted")
+	cap.Emit(ctx, sig,
+		capitan.NewStringKey("zeta").Field("z"),
+		capitan.NewStringKey("alpha").Field("a"),
+		capitan.NewStringKey("middle").Field("m"),
+	)
+
+	if !capture.WaitForCount(1, 2*time.Second) {
+		t.Fatal("timed out waiting for log record")
+	}
+
+	var keys []string
+	capture.Records()[0].WalkAttributes(func(kv log.KeyValue) bool {
+		keys = append(keys, kv.Key)
+		return true
+	})
+	if !slices.IsSorted(keys) {
+		t.Errorf("attribute keys not sorted: %v", keys)
+	}
+	for _, want := range []string{"alpha", "middle", "zeta", "capitan.signal"} {
+		if !slices.Contains(keys, want) {
+			t.Errorf("missing attribute %q in %v", want, keys)
+		}
+	}
+}
+
+func TestCapitanObserver_ExcludeFields(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	logProvider := apertesting.NewMockLoggerProvider()
+	capture := logProvider.Capture()
+
+	sh, err := New(cap, logProvider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	if err := sh.Apply(Schema{ExcludeFields: []string{"password"}}); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	sig := capitan.NewSignal("user.login", "User login")
+	cap.Emit(ctx, sig,
+		capitan.NewStringKey("user_id").Field("user-1"),
+		capitan.NewStringKey("password").Field("hunter2"),
+	)
+
+	if !capture.WaitForCount(1, 2*time.Second) {
+		t.Fatal("timed out waiting for log record")
+	}
+
+	attrs := apertesting.LogRecordAttributes(capture.Records()[0])
+	if _, ok := attrs["password"]; ok {
+		t.Errorf("excluded field password was logged: %v", attrs)
+	}
+	if attrs["user_id"] != "user-1" {
+		t.Errorf("user_id = %v, want user-1", attrs["user_id"])
+	}
+}
+
+func TestCapitanObserver_MirrorMetaAttributes(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	logProvider := apertesting.NewMockLoggerProvider()
+	capture := logProvider.Capture()
+
+	sh, err := New(cap, logProvider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	sig := capitan.NewSignal("order.mirrored", "Order mirrored")
+
+	// Off by default
+	cap.Warn(ctx, sig)
+	if !capture.WaitForCount(1, 2*time.Second) {
+		t.Fatal("timed out waiting for log record")
+	}
+	capture.Records()[0].WalkAttributes(func(kv log.KeyValue) bool {
+		if kv.Key == "event.severity" || kv.Key == "event.timestamp" {
+			t.Errorf("unexpected attribute %q without mirror_meta_attributes", kv.Key)
+		}
+		return true
+	})
+
+	if err := sh.Apply(Schema{MirrorMetaAttributes: true}); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	cap.Warn(ctx, sig)
+	if !capture.WaitForCount(2, 2*time.Second) {
+		t.Fatal("timed out waiting for log record")
+	}
+	record := capture.Records()[1]
+
+	attrs := make(map[string]string)
+	record.WalkAttributes(func(kv log.KeyValue) bool {
+		attrs[kv.Key] = kv.Value.AsString()
+		return true
+	})
+
+	if got := attrs["event.severity"]; got != string(capitan.SeverityWarn) {
+		t.Errorf("event.severity = %q, want %q", got, capitan.SeverityWarn)
+	}
+	ts, err := time.Parse(time.RFC3339Nano, attrs["event.timestamp"])
+	if err != nil {
+		t.Fatalf("event.timestamp %q does not parse: %v", attrs["event.timestamp"], err)
+	}
+	if !ts.Equal(record.Timestamp()) {
+		t.Errorf("event.timestamp = %v, want record timestamp %v", ts, record.Timestamp())
+	}
+}
+
+func TestCapitanObserver_LogsEmittedMetric(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	logProvider := apertesting.NewMockLoggerProvider()
+	capture := logProvider.Capture()
+
+	sh, err := New(cap, logProvider, meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(Schema{
+		LogsEmittedMetric: true,
+		Logs:              &LogSchema{Whitelist: []string{"order.created", "order.shipped"}},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	created := capitan.NewSignal("order.created", "Order created")
+	shipped := capitan.NewSignal("order.shipped", "Order shipped")
+	filtered := capitan.NewSignal("order.filtered", "Order filtered")
+
+	cap.Emit(ctx, created)
+	cap.Emit(ctx, created)
+	cap.Emit(ctx, shipped)
+	cap.Emit(ctx, filtered)
+
+	if !capture.WaitForCount(3, 2*time.Second) {
+		t.Fatal("timed out waiting for log records")
+	}
+	sh.Flush(ctx)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("failed to collect metrics: %v", err)
+	}
+
+	got := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != logsEmittedMetricName {
+				continue
+			}
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				t.Fatalf("expected int64 sum, got %T", m.Data)
+			}
+			for _, dp := range sum.DataPoints {
+				signal, _ := dp.Attributes.Value("signal")
+				got[signal.AsString()] = dp.Value
+			}
+		}
+	}
+
+	if got["order.created"] != 2 || got["order.shipped"] != 1 {
+		t.Errorf("expected order.created=2 order.shipped=1, got %v", got)
+	}
+	if _, ok := got["order.filtered"]; ok {
+		t.Errorf("filtered signal should not be counted, got %v", got)
+	}
+}
+
+// countSignalLogs counts records carrying the given capitan.signal attribute.
+func countSignalLogs(records []log.Record, signalName string) int {
+	count := 0
+	for i := range records {
+		records[i].WalkAttributes(func(kv log.KeyValue) bool {
+			if kv.Key == "capitan.signal" && kv.Value.AsString() == signalName {
+				count++
+				return false
+			}
+			return true
+		})
+	}
+	return count
+}
+
+func TestSetLogFilter(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	logger := newMockLogger()
+	sh, err := New(cap, &mockLoggerProvider{logger: logger}, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	orderCreated := capitan.NewSignal("order.created", "Order created")
+	auditEvent := capitan.NewSignal("audit.event", "Audit event")
+	requestStarted := capitan.NewSignal("request.started", "Request started")
+	requestID := capitan.NewStringKey("request_id")
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{Signal: "order.created", Name: "orders_total", Type: "counter"},
+		},
+		Traces: []TraceSchema{
+			{Start: "request.started", End: "request.completed", CorrelationKey: "request_id"},
+		},
+		Logs: &LogSchema{
+			Whitelist: []string{"order.created"},
+		},
+	}
+
+	err = sh.Apply(schema)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	observer := sh.capitanObserver
+	metrics := observer.metricsHandler
+	traces := observer.tracesHandler
+
+	// Leave a span pending across the filter change
+	cap.Emit(ctx, requestStarted, requestID.Field("REQ-1"))
+	cap.Emit(ctx, auditEvent)
+	cap.Emit(ctx, orderCreated)
+	logger.waitForRecords(1, time.Second)
+	time.Sleep(50 * time.Millisecond)
+
+	records := logger.getRecords()
+	if n := countSignalLogs(records, "audit.event"); n != 0 {
+		t.Errorf("audit.event logged %d times before filter change, want 0", n)
+	}
+
+	// Swap the whitelist: audit.event in, order.created out
+	sh.SetLogFilter([]string{"audit.event"}, nil)
+
+	cap.Emit(ctx, auditEvent)
+	cap.Emit(ctx, orderCreated)
+	logger.waitForRecords(2, time.Second)
+	time.Sleep(50 * time.Millisecond)
+
+	records = logger.getRecords()
+	if n := countSignalLogs(records, "audit.event"); n != 1 {
+		t.Errorf("audit.event logged %d times after filter change, want 1", n)
+	}
+	if n := countSignalLogs(records, "order.created"); n != 1 {
+		t.Errorf("order.created logged %d times, want 1 (only before filter change)", n)
+	}
+
+	// Blacklist only: everything except audit.event is logged
+	sh.SetLogFilter(nil, []string{"audit.event"})
+
+	cap.Emit(ctx, auditEvent)
+	cap.Emit(ctx, orderCreated)
+	logger.waitForRecords(3, time.Second)
+	time.Sleep(50 * time.Millisecond)
+
+	records = logger.getRecords()
+	if n := countSignalLogs(records, "audit.event"); n != 1 {
+		t.Errorf("audit.event logged %d times after blacklist, want 1", n)
+	}
+	if n := countSignalLogs(records, "order.created"); n != 2 {
+		t.Errorf("order.created logged %d times after blacklist, want 2", n)
+	}
+
+	// Observer, metric instruments and pending spans must be untouched
+	if sh.capitanObserver != observer {
+		t.Error("SetLogFilter replaced the observer")
+	}
+	if sh.capitanObserver.metricsHandler != metrics {
+		t.Error("SetLogFilter rebuilt the metrics handler")
+	}
+	if sh.capitanObserver.tracesHandler != traces {
+		t.Error("SetLogFilter rebuilt the traces handler")
+	}
+	traces.mu.Lock()
+	pending := traces.pendingStarts.Len()
+	traces.mu.Unlock()
+	if pending != 1 {
+		t.Errorf("expected 1 pending span, got %d", pending)
+	}
+}
+
+func TestSetLogFilter_KeepsLogSettings(t *testing.T) {
+	cap := capitan.New()
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(Schema{
+		Logs: &LogSchema{
+			Whitelist:        []string{"order.created"},
+			BodyTemplate:     "order {order_id}",
+			BodyTemplates:    map[string]string{"order.shipped": "shipped {order_id}"},
+			MinFields:        1,
+			StructuredErrors: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	sh.SetLogFilter(nil, []string{"audit.event"})
+
+	sh.mu.RLock()
+	logs := sh.config.Logs
+	sh.mu.RUnlock()
+	if logs == nil {
+		t.Fatal("expected log settings to survive SetLogFilter")
+	}
+	if logs.WhitelistNames != nil || len(logs.BlacklistNames) != 1 {
+		t.Errorf("lists = %v / %v, want nil / [audit.event]", logs.WhitelistNames, logs.BlacklistNames)
+	}
+	if logs.BodyTemplate != "order {order_id}" || logs.BodyTemplates["order.shipped"] != "shipped {order_id}" {
+		t.Errorf("body templates lost: %+v", logs)
+	}
+	if logs.MinFields != 1 {
+		t.Errorf("MinFields = %d, want 1", logs.MinFields)
+	}
+	if !logs.StructuredErrors {
+		t.Error("expected StructuredErrors to survive SetLogFilter")
+	}
+}
+
+func TestSetLogFilterSignals(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	logger := newMockLogger()
+	sh, err := New(cap, &mockLoggerProvider{logger: logger}, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	orderCreated := capitan.NewSignal("order.created", "Order created")
+	auditEvent := capitan.NewSignal("audit.event", "Audit event")
+
+	sh.SetLogFilterSignals([]capitan.Signal{orderCreated}, nil)
+
+	cap.Emit(ctx, auditEvent)
+	cap.Emit(ctx, orderCreated)
+	logger.waitForRecords(1, time.Second)
+	time.Sleep(50 * time.Millisecond)
+
+	records := logger.getRecords()
+	if n := countSignalLogs(records, "order.created"); n != 1 {
+		t.Errorf("order.created logged %d times, want 1", n)
+	}
+	if n := countSignalLogs(records, "audit.event"); n != 0 {
+		t.Errorf("audit.event logged %d times, want 0", n)
+	}
+
+	// The config matches what SetLogFilter would store
+	sh.mu.RLock()
+	logs := sh.config.Logs
+	sh.mu.RUnlock()
+	if logs == nil || len(logs.WhitelistNames) != 1 || logs.WhitelistNames[0] != "order.created" || logs.BlacklistNames != nil {
+		t.Errorf("config.Logs = %+v, want whitelist [order.created]", logs)
+	}
+}
+
+func TestLogFilter_Allows(t *testing.T) {
+	tests := []struct {
+		name      string
+		whitelist []string
+		blacklist []string
+		signal    string
+		want      bool
+	}{
+		{"no filter", nil, nil, "any", true},
+		{"whitelisted", []string{"a"}, nil, "a", true},
+		{"not whitelisted", []string{"a"}, nil, "b", false},
+		{"blacklisted", nil, []string{"a"}, "a", false},
+		{"not blacklisted", nil, []string{"a"}, "b", true},
+		{"blacklist wins over whitelist", []string{"a"}, []string{"a"}, "a", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newLogFilter(tt.whitelist, tt.blacklist)
+			if got := f.allows(tt.signal); got != tt.want {
+				t.Errorf("allows(%q) = %v, want %v", tt.signal, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogFilter_Whitelisted(t *testing.T) {
+	tests := []struct {
+		name      string
+		whitelist []string
+		blacklist []string
+		signal    string
+		want      bool
+	}{
+		{"no filter", nil, nil, "any", true},
+		{"whitelisted", []string{"a"}, nil, "a", true},
+		{"not whitelisted", []string{"a"}, nil, "b", false},
+		{"blacklist only", nil, []string{"a"}, "a", true},
+		{"blacklist ignored", []string{"a"}, []string{"a"}, "a", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newLogFilter(tt.whitelist, tt.blacklist)
+			if got := f.whitelisted(tt.signal); got != tt.want {
+				t.Errorf("whitelisted(%q) = %v, want %v", tt.signal, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCapitanObserver_StrictWhitelist(t *testing.T) {
+	tests := []struct {
+		name            string
+		strict          bool
+		wantCPUCount    int64
+		wantOrdersCount int64
+	}{
+		{"independent by default", false, 1, 1},
+		{"strict gates metrics", true, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cap := capitan.New()
+
+			reader := sdkmetric.NewManualReader()
+			meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+			defer meterProvider.Shutdown(ctx)
+
+			recorder := tracetest.NewSpanRecorder()
+			traceProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+			defer traceProvider.Shutdown(ctx)
+
+			sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, traceProvider)
+			if err != nil {
+				t.Fatalf("failed to create Aperture: %v", err)
+			}
+			defer sh.Close()
+
+			err = sh.Apply(Schema{
+				Metrics: []MetricSchema{
+					{Signal: "order.created", Name: "orders_total", Type: "counter"},
+					{Signal: "cpu.sampled", Name: "cpu_samples_total", Type: "counter"},
+				},
+				Traces: []TraceSchema{
+					{Start: "job.started", End: "job.done", CorrelationKey: "job_id"},
+				},
+				Logs:            &LogSchema{Whitelist: []string{"order.created"}},
+				StrictWhitelist: tt.strict,
+			})
+			if err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+
+			jobID := capitan.NewStringKey("job_id")
+			cap.Emit(ctx, capitan.NewSignal("order.created", "Order created"))
+			cap.Emit(ctx, capitan.NewSignal("cpu.sampled", "CPU sampled"))
+			cap.Emit(ctx, capitan.NewSignal("job.started", "Job started"), jobID.Field("J-1"))
+			cap.Emit(ctx, capitan.NewSignal("job.done", "Job done"), jobID.Field("J-1"))
+			time.Sleep(100 * time.Millisecond)
+
+			var rm metricdata.ResourceMetrics
+			if err := reader.Collect(ctx, &rm); err != nil {
+				t.Fatalf("collect failed: %v", err)
+			}
+
+			counts := make(map[string]int64)
+			for _, sm := range rm.ScopeMetrics {
+				for _, m := range sm.Metrics {
+					if data, ok := m.Data.(metricdata.Sum[int64]); ok {
+						for _, dp := range data.DataPoints {
+							counts[m.Name] += dp.Value
+						}
+					}
+				}
+			}
+
+			if counts["orders_total"] != tt.wantOrdersCount {
+				t.Errorf("orders_total = %d, want %d", counts["orders_total"], tt.wantOrdersCount)
+			}
+			if counts["cpu_samples_total"] != tt.wantCPUCount {
+				t.Errorf("cpu_samples_total = %d, want %d", counts["cpu_samples_total"], tt.wantCPUCount)
+			}
+
+			wantSpans := 1
+			if tt.strict {
+				wantSpans = 0
+			}
+			if got := len(recorder.Ended()); got != wantSpans {
+				t.Errorf("spans = %d, want %d", got, wantSpans)
+			}
+		})
+	}
+}
+
+func TestCapitanObserver_LogMinFields(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	logProvider := apertesting.NewMockLoggerProvider()
+	capture := logProvider.Capture()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	sh, err := New(cap, logProvider, meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(Schema{
+		Metrics: []MetricSchema{
+			{Signal: "heartbeat", Name: "heartbeats_total", Type: "counter"},
+		},
+		Logs: &LogSchema{MinFields: 1},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	heartbeat := capitan.NewSignal("heartbeat", "Heartbeat")
+	nodeKey := capitan.NewStringKey("node")
+
+	cap.Emit(ctx, heartbeat)                       // marker: not logged
+	cap.Emit(ctx, heartbeat, nodeKey.Field("n-1")) // logged
+	time.Sleep(100 * time.Millisecond)
+
+	if got := countSignalLogs(capture.Records(), "heartbeat"); got != 1 {
+		t.Errorf("expected 1 logged heartbeat, got %d", got)
+	}
+
+	// Metrics still see every event
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	var total int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if data, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == "heartbeats_total" {
+				for _, dp := range data.DataPoints {
+					total += dp.Value
+				}
+			}
+		}
+	}
+	if total != 2 {
+		t.Errorf("heartbeats_total = %d, want 2", total)
+	}
+}
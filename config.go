@@ -2,14 +2,17 @@ package aperture
 
 import (
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
 )
 
 // config is the internal runtime configuration for aperture.
 // Users configure via Schema (YAML/JSON), which is converted to config via buildConfig().
 type config struct {
 	// Pointers first for optimal GC pointer bitmap
-	// Logs configures which signals should be logged.
-	// If nil or empty, all signals are logged (default behavior).
+	// Logs configures which signals are logged and how their records are built.
+	// If nil, all signals are logged with the defaults.
 	Logs *logConfig
 
 	// ContextExtraction specifies context keys to extract and add to OTEL signals.
@@ -23,11 +26,110 @@ type config struct {
 	// Traces configures signal pairs that should be correlated into spans.
 	Traces []traceConfig
 
+	// ExcludeFields lists event field keys dropped before attribute conversion
+	// for logs, metrics, and stdout.
+	ExcludeFields []string
+
+	// StdoutFormat selects the slog handler used for stdout logging.
+	StdoutFormat StdoutFormat
+
+	// ScopeName is the instrumentation scope of event logs, metrics, and spans.
+	// If empty, the default scopes are used.
+	ScopeName string
+
+	// MaxPending caps pending trace starts and pending trace ends, each.
+	// Zero means no cap.
+	MaxPending int
+
+	// MaxAttributeBytes truncates longer string and bytes field values.
+	// Zero means no limit.
+	MaxAttributeBytes int
+
+	// UnusedConfigWarmup is how long to wait for each configured signal before
+	// reporting it with SignalConfigUnused. Zero disables the report.
+	UnusedConfigWarmup time.Duration
+
 	// StdoutLogging enables duplication of OTEL output to stdout.
 	// When true, all OTEL signals are logged to stdout in human-readable format using slog.
 	StdoutLogging bool
+
+	// StrictWhitelist restricts metric and trace handling to signals on the
+	// log whitelist. No effect when no whitelist is set.
+	StrictWhitelist bool
+
+	// BestEffort skips metrics whose instruments cannot be created, emitting
+	// SignalConfigError, instead of failing the whole Apply.
+	BestEffort bool
+
+	// PendingSpanMetric exports pending trace starts and ends as an observable gauge.
+	PendingSpanMetric bool
+
+	// LogsEmittedMetric counts forwarded log records per signal.
+	LogsEmittedMetric bool
+
+	// KeepUnserializable emits custom fields that fail JSON serialization as a
+	// placeholder instead of dropping them.
+	KeepUnserializable bool
+
+	// SortAttributes emits log and span attributes sorted by key.
+	SortAttributes bool
+
+	// MirrorMetaAttributes copies each event's severity and timestamp into the
+	// event.severity and event.timestamp log attributes.
+	MirrorMetaAttributes bool
+}
+
+// Default instrumentation scope names, used when config.ScopeName is empty.
+const (
+	defaultScopeName          = "capitan"
+	defaultInternalLoggerName = "aperture.internal"
+	defaultInternalMeterName  = "aperture"
+)
+
+// logs returns the log settings, or the zero value if none are set.
+func (c *config) logs() logConfig {
+	if c.Logs == nil {
+		return logConfig{}
+	}
+	return *c.Logs
 }
 
+// scopeName returns the scope for event logs, metrics, and spans.
+func (c *config) scopeName() string {
+	if c.ScopeName == "" {
+		return defaultScopeName
+	}
+	return c.ScopeName
+}
+
+// internalLoggerName returns the scope for diagnostic log records.
+func (c *config) internalLoggerName() string {
+	if c.ScopeName == "" {
+		return defaultInternalLoggerName
+	}
+	return c.ScopeName + ".internal"
+}
+
+// internalMeterName returns the scope for aperture's own metrics, such as the
+// pending span gauge.
+func (c *config) internalMeterName() string {
+	if c.ScopeName == "" {
+		return defaultInternalMeterName
+	}
+	return c.ScopeName + ".internal"
+}
+
+// StdoutFormat specifies the output format for stdout logging.
+type StdoutFormat string
+
+const (
+	// StdoutFormatText writes key=value lines via slog.TextHandler (default).
+	StdoutFormatText StdoutFormat = "text"
+
+	// StdoutFormatJSON writes one JSON object per line via slog.JSONHandler.
+	StdoutFormatJSON StdoutFormat = "json"
+)
+
 // MetricType specifies the type of OTEL metric instrument.
 type MetricType string
 
@@ -49,6 +151,62 @@ const (
 	MetricTypeHistogram MetricType = "histogram"
 )
 
+// DurationUnit specifies how duration field values are recorded as metric values.
+type DurationUnit string
+
+const (
+	// DurationUnitMilliseconds records durations as float64 milliseconds.
+	// Default for histograms.
+	DurationUnitMilliseconds DurationUnit = "ms"
+
+	// DurationUnitNanoseconds records durations as exact int64 nanoseconds.
+	// Default for gauges and up/down counters.
+	DurationUnitNanoseconds DurationUnit = "ns"
+)
+
+// HistogramType specifies the aggregation used for a histogram metric.
+type HistogramType string
+
+const (
+	// HistogramTypeExplicit uses the SDK's explicit bucket boundaries (default).
+	HistogramTypeExplicit HistogramType = "explicit"
+
+	// HistogramTypeExponential uses base-2 exponential buckets, which adapt to
+	// wide-range data such as latencies. Requires the views from [HistogramViews]
+	// on the meter provider.
+	HistogramTypeExponential HistogramType = "exponential"
+)
+
+// bucketPresets are the named explicit bucket boundary sets for histograms,
+// selected with MetricSchema.BucketsPreset.
+var bucketPresets = map[string][]float64{
+	// Request latencies in milliseconds, 5ms to 10s.
+	"http_latency_ms": {5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000},
+
+	// Latencies in seconds, 5ms to 10s (the Prometheus client defaults).
+	"latency_seconds": {0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+
+	// Payload sizes in bytes, powers of four from 64B to 16MiB.
+	"bytes": {64, 256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304, 16777216},
+}
+
+// GaugeAggregation specifies how gauge values are combined within a reporting interval.
+type GaugeAggregation string
+
+const (
+	// GaugeAggregationLast reports the most recently recorded value (default).
+	GaugeAggregationLast GaugeAggregation = "last"
+
+	// GaugeAggregationMax reports the largest value recorded since the last collection.
+	GaugeAggregationMax GaugeAggregation = "max"
+
+	// GaugeAggregationMin reports the smallest value recorded since the last collection.
+	GaugeAggregationMin GaugeAggregation = "min"
+
+	// GaugeAggregationSum reports the sum of values recorded since the last collection.
+	GaugeAggregationSum GaugeAggregation = "sum"
+)
+
 // metricConfig defines a signal-to-metric conversion (internal).
 type metricConfig struct {
 	// SignalName is the name of the capitan signal to observe.
@@ -68,13 +226,92 @@ type metricConfig struct {
 
 	// Description is optional metric description.
 	Description string
+
+	// Unit is the optional instrument unit.
+	Unit string
+
+	// DurationUnit controls how duration values from ValueKeyName are recorded.
+	// Defaults to milliseconds for histograms and nanoseconds otherwise.
+	DurationUnit DurationUnit
+
+	// Aggregation controls how gauge values are combined within a reporting interval.
+	// Only applies to MetricTypeGauge. Defaults to GaugeAggregationLast.
+	Aggregation GaugeAggregation
+
+	// Attributes are constant attributes added to every measurement of this metric.
+	// They take precedence over event fields with the same key.
+	Attributes map[string]string
+
+	// ParseStringValue enables parsing string value fields as float64 numbers.
+	// When false, string value fields are treated as missing.
+	ParseStringValue bool
+
+	// AttributeRename maps event field keys to the attribute names used for this
+	// metric (e.g. order_status → status). Unmatched keys pass through unchanged.
+	AttributeRename map[string]string
+
+	// AttributeAllowlist names the event field keys recorded as dimensions for
+	// this metric; other fields are dropped. Empty keeps all fields.
+	AttributeAllowlist []string
+
+	// IncludeSeverityAttribute adds the event's capitan severity as a
+	// "severity" attribute. Off by default to avoid cardinality surprises.
+	IncludeSeverityAttribute bool
+
+	// IncludeSignalAttribute adds the event's signal name as a "signal"
+	// attribute. Off by default to avoid cardinality surprises.
+	IncludeSignalAttribute bool
+
+	// HistogramType is the aggregation the schema asks for. Only applies to
+	// MetricTypeHistogram; empty means HistogramTypeExplicit. The meter
+	// provider's views decide the aggregation, so this is kept to report a
+	// type changed by Apply.
+	HistogramType HistogramType
+
+	// Buckets are the explicit histogram bucket boundaries, resolved from
+	// MetricSchema.Buckets or BucketsPreset. Empty uses the SDK defaults.
+	Buckets []float64
+
+	// FilterKeyName names the field that must equal FilterValue for the
+	// measurement to be recorded. Empty records every event.
+	FilterKeyName string
+
+	// FilterValue is compared with the filter field's value in string form.
+	// Defaults to "true" when FilterKeyName is set.
+	FilterValue string
 }
 
-// logConfig configures log filtering (internal).
+// logConfig configures log filtering and rendering (internal).
 type logConfig struct {
 	// WhitelistNames specifies signal names to log.
 	// If empty, all signals are logged.
 	WhitelistNames []string
+
+	// BlacklistNames specifies signal names to never log.
+	// Takes precedence over WhitelistNames.
+	BlacklistNames []string
+
+	// BodyTemplate renders log bodies from event fields.
+	// If empty, the signal description is used.
+	BodyTemplate string
+
+	// BodyTemplates maps signal names to body templates that override
+	// BodyTemplate for those signals.
+	BodyTemplates map[string]string
+
+	// MinFields skips logging events with fewer fields. Zero logs every event.
+	MinFields int
+
+	// StructuredErrors adds type and code attributes for error fields in
+	// OTEL and stdout logs.
+	StructuredErrors bool
+}
+
+// isZero reports whether lc sets nothing, so config.Logs can be left nil.
+func (lc *logConfig) isZero() bool {
+	return len(lc.WhitelistNames) == 0 && len(lc.BlacklistNames) == 0 &&
+		lc.BodyTemplate == "" && len(lc.BodyTemplates) == 0 &&
+		lc.MinFields == 0 && !lc.StructuredErrors
 }
 
 // traceConfig defines a signal pair that forms a trace span (internal).
@@ -82,8 +319,9 @@ type traceConfig struct {
 	// StartSignalName is the name of the signal that begins the span.
 	StartSignalName string
 
-	// EndSignalName is the name of the signal that completes the span.
-	EndSignalName string
+	// EndSignalNames are the signals that complete the span. The first to
+	// arrive for a correlation ID closes it.
+	EndSignalNames []string
 
 	// CorrelationKeyName is the name of the field key used to correlate start/end events.
 	// Both start and end events must have this field with matching values.
@@ -93,11 +331,27 @@ type traceConfig struct {
 	// If empty, uses the start signal name.
 	SpanName string
 
+	// SpanNameTemplate, if set, names the span from start event fields.
+	// SpanName is the fallback when a placeholder's field is missing.
+	SpanNameTemplate string
+
 	// SpanTimeout is the maximum duration to wait for an end event.
 	// If the end event doesn't arrive within this timeout, the span is
 	// automatically ended and cleaned up to prevent memory leaks.
 	// Defaults to 5 minutes if not specified or zero.
 	SpanTimeout time.Duration
+
+	// SampleRate is the fraction (0, 1] of correlated pairs that create spans.
+	// Sampling is deterministic per correlation ID. Defaults to 1 (all pairs).
+	SampleRate float64
+
+	// MergeEndContext extracts trace context keys from the end event's context
+	// as well as the start's. End values win on key collision.
+	MergeEndContext bool
+
+	// DurationMetric names a histogram recording each span's duration in
+	// milliseconds. Empty means no histogram.
+	DurationMetric string
 }
 
 // ContextKey defines a key-name pair for extracting values from context.Context.
@@ -108,6 +362,11 @@ type ContextKey struct {
 
 	// Name is the attribute name to use in OTEL signals.
 	Name string
+
+	// logTransform and metricTransform convert the value to attributes in
+	// place of the built-in conversion. Set from registered transformers.
+	logTransform    func(any) []log.KeyValue
+	metricTransform func(any) []attribute.KeyValue
 }
 
 // contextExtractionConfig defines context values to extract for each signal type (internal).
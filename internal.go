@@ -2,6 +2,7 @@ package aperture
 
 import (
 	"context"
+	"sync/atomic"
 
 	"github.com/zoobzio/capitan"
 	"go.opentelemetry.io/otel/log"
@@ -10,7 +11,8 @@ import (
 // Diagnostic signals emitted by Aperture for operational visibility.
 //
 // These signals are written to the OTEL logger at DEBUG severity with a
-// "aperture.signal" attribute containing the signal name. They help diagnose
+// "aperture.signal" attribute containing the signal name. Event log records use
+// the same attribute, so match on the "aperture:" prefix. They help diagnose
 // configuration issues and unexpected runtime conditions.
 //
 // Filter for these in your log aggregator using:
@@ -41,6 +43,45 @@ var (
 	// Resolution: Ensure the signal is emitted with the required value field.
 	SignalMetricValueMissing = capitan.NewSignal("aperture:metric:value_missing", "metric value could not be extracted from event")
 
+	// SignalMetricValueInvalid is emitted when a metric configured with
+	// parse_string_value receives a string value field that is not a number.
+	//
+	// Attributes:
+	//   - signal: The originating capitan signal name
+	//   - metric_name: The OTEL metric name
+	//   - value_key: The value field key name
+	//   - raw_value: The string that failed to parse
+	//
+	// Resolution: Ensure the producer emits a decimal number in the value field,
+	// or migrate it to a numeric key type.
+	SignalMetricValueInvalid = capitan.NewSignal("aperture:metric:value_invalid", "metric string value could not be parsed as a number")
+
+	// SignalMetricFilterMissing is emitted when a metric configured with
+	// filter_key receives an event without the filter field. The measurement
+	// is skipped.
+	//
+	// Attributes:
+	//   - signal: The originating capitan signal name
+	//   - metric_name: The OTEL metric name
+	//   - filter_key: The expected field key name
+	//
+	// Resolution: Ensure the signal is emitted with the filter field.
+	SignalMetricFilterMissing = capitan.NewSignal("aperture:metric:filter_missing", "metric filter field missing from event")
+
+	// SignalMetricHistogramTypeChanged is emitted when Apply changes a
+	// histogram's histogram_type. Aggregation is set by the meter provider's
+	// views, which cannot change after the provider is built, so the metric
+	// keeps its old aggregation.
+	//
+	// Attributes:
+	//   - signal: The configured signal name
+	//   - metric_name: The OTEL metric name
+	//   - reason: The previous and requested histogram types
+	//
+	// Resolution: Recreate the meter provider with [HistogramViews] for the
+	// new schema, or restore the previous histogram_type.
+	SignalMetricHistogramTypeChanged = capitan.NewSignal("aperture:metric:histogram_type_changed", "histogram_type changed; meter provider views still use the old aggregation")
+
 	// SignalTraceCorrelationMissing is emitted when a trace start or end event
 	// lacks the correlation_key field required to match spans.
 	//
@@ -51,6 +92,126 @@ var (
 	//
 	// Resolution: Ensure trace events include the correlation key field.
 	SignalTraceCorrelationMissing = capitan.NewSignal("aperture:trace:correlation_missing", "trace event missing correlation ID field")
+
+	// SignalTraceDuplicateStart is emitted when a start event arrives for a
+	// correlation ID that already has a pending start (e.g. a retried operation).
+	//
+	// The earliest start is kept and the duplicate is discarded, so the resulting
+	// span covers the operation from its first attempt.
+	//
+	// Attributes:
+	//   - correlation_id: The duplicated correlation ID
+	//   - span_name: The configured span name
+	//
+	// Resolution: Ensure each operation emits a single start event, or use a
+	// distinct correlation ID per attempt if retries should be separate spans.
+	SignalTraceDuplicateStart = capitan.NewSignal("aperture:trace:duplicate_start", "duplicate start event for pending span")
+
+	// SignalTraceDuplicateEnd is emitted when a trace config has several end
+	// signals and a second end arrives for the same correlation ID.
+	//
+	// The first end closes the span and the later one is discarded. Ends on
+	// different signals are handled by separate capitan workers, so which one
+	// counts as first is not guaranteed to follow emit order.
+	//
+	// Attributes:
+	//   - signal: The discarded end signal name
+	//   - correlation_id: The duplicated correlation ID
+	//   - span_name: The configured span name
+	//
+	// Resolution: Ensure each operation emits exactly one of its end signals.
+	SignalTraceDuplicateEnd = capitan.NewSignal("aperture:trace:duplicate_end", "duplicate end event for span; first end wins")
+
+	// SignalTraceNegativeDuration is emitted when a correlated end event has an
+	// earlier timestamp than its start event. The span is ended at its start
+	// time (zero duration) instead of with a negative duration.
+	//
+	// Attributes:
+	//   - correlation_id: The correlation ID of the span
+	//   - span_name: The configured span name
+	//   - skew: How far the end preceded the start (Go duration string)
+	//
+	// Resolution: Check for clock skew between emitting hosts, or for start and
+	// end signals emitted in the wrong order.
+	SignalTraceNegativeDuration = capitan.NewSignal("aperture:trace:negative_duration", "span end timestamp precedes start timestamp")
+
+	// SignalTracePendingOverflow is emitted when a start or end event cannot be
+	// held for correlation because max_pending events of that kind are already
+	// pending. The new event is dropped; pending events are kept, so no span is
+	// created for the dropped event's correlation ID.
+	//
+	// Attributes:
+	//   - signal: The originating capitan signal name
+	//   - correlation_id: The dropped correlation ID
+	//   - span_name: The configured span name
+	//   - max_pending: The configured cap
+	//
+	// Resolution: Check for starts or ends that never pair up (see
+	// SignalTraceExpired), lower span_timeout, or raise max_pending.
+	SignalTracePendingOverflow = capitan.NewSignal("aperture:trace:pending_overflow", "pending span limit reached; event dropped")
+
+	// SignalValueClamped is emitted when an unsigned field value exceeds
+	// math.MaxInt64 and is clamped while being converted to an OTEL int64
+	// log attribute or metric attribute. Metric values are not clamped: they
+	// are recorded on the float64 instrument instead, which keeps the
+	// magnitude but not the low bits.
+	//
+	// Attributes:
+	//   - signal: The originating capitan signal name
+	//   - field_key: The clamped field key name
+	//   - original_value: The original unsigned value (decimal string)
+	//
+	// Resolution: The recorded value is math.MaxInt64, not the original. Emit
+	// such values as float64 or string fields if the full range matters.
+	SignalValueClamped = capitan.NewSignal("aperture:value:clamped", "unsigned field value clamped to max int64")
+
+	// SignalTransformFailed is emitted when a custom-typed field value cannot
+	// be JSON serialized for a log or metric attribute (e.g. it holds a channel
+	// or func, or its MarshalJSON returns an error).
+	//
+	// Attributes:
+	//   - signal: The originating capitan signal name
+	//   - field_key: The field key name
+	//   - reason: The JSON marshal error
+	//
+	// Resolution: The field is dropped unless keep_unserializable is set, in
+	// which case a "<unserializable:Type>" placeholder is recorded. Implement
+	// json.Marshaler on the type, or emit a serializable view of it.
+	SignalTransformFailed = capitan.NewSignal("aperture:transform:failed", "custom field value could not be serialized")
+
+	// SignalServiceNameMissing is emitted once when the first span is created
+	// and the trace provider's resource has no resolvable service.name.
+	//
+	// Attributes:
+	//   - reason: Why the service name is considered missing
+	//
+	// Resolution: Build provider resources with [WithServiceInfo] or set
+	// OTEL_SERVICE_NAME. Check resources directly with [ValidateResource].
+	SignalServiceNameMissing = capitan.NewSignal("aperture:resource:service_name_missing", "trace provider resource lacks service.name")
+
+	// SignalConfigError is emitted when best_effort is set and a metric
+	// instrument cannot be created, e.g. because its name violates a backend
+	// naming rule. The metric is skipped; other metrics are created as usual.
+	//
+	// Attributes:
+	//   - signal: The configured signal name
+	//   - metric_name: The OTEL metric name
+	//   - reason: The instrument creation error
+	//
+	// Resolution: Fix the metric config named in the diagnostic.
+	SignalConfigError = capitan.NewSignal("aperture:config:error", "metric instrument could not be created; metric skipped")
+
+	// SignalConfigUnused is emitted once per metric or trace config whose signal
+	// was not seen within the unused_config_warmup window after Apply. Opt-in.
+	//
+	// Attributes:
+	//   - signal: The configured signal name that was never seen
+	//   - metric_name: The OTEL metric name (metric configs)
+	//   - span_name: The configured span name (trace configs)
+	//
+	// Resolution: Check the schema for a typo in the signal name, or confirm
+	// that the signal is expected to be emitted in this environment.
+	SignalConfigUnused = capitan.NewSignal("aperture:config:unused", "configured signal not seen within warmup window")
 )
 
 // Internal field keys for diagnostic events.
@@ -62,6 +223,12 @@ var (
 	internalMetricName     = capitan.NewStringKey("metric_name")
 	internalValueKey       = capitan.NewStringKey("value_key")
 	internalCorrelationKey = capitan.NewStringKey("correlation_key")
+	internalFieldKey       = capitan.NewStringKey("field_key")
+	internalOriginalValue  = capitan.NewStringKey("original_value")
+	internalRawValue       = capitan.NewStringKey("raw_value")
+	internalFilterKey      = capitan.NewStringKey("filter_key")
+	internalSkew           = capitan.NewStringKey("skew")
+	internalMaxPending     = capitan.NewStringKey("max_pending")
 )
 
 // internalObserver handles Aperture's private diagnostic events.
@@ -69,7 +236,8 @@ var (
 type internalObserver struct {
 	capitan  *capitan.Capitan
 	observer *capitan.Observer
-	logger   log.Logger
+	logger   atomic.Pointer[log.Logger]                            // swapped on Apply when scope_name changes
+	callback atomic.Pointer[func(capitan.Signal, []capitan.Field)] // set by OnDiagnostic
 }
 
 // newInternalObserver creates the internal diagnostic system.
@@ -78,8 +246,8 @@ func newInternalObserver(logger log.Logger) *internalObserver {
 
 	io := &internalObserver{
 		capitan: internal,
-		logger:  logger,
 	}
+	io.setLogger(logger)
 
 	io.observer = internal.Observe(io.handleEvent)
 
@@ -96,8 +264,11 @@ func (io *internalObserver) handleEvent(ctx context.Context, e *capitan.Event) {
 	record.SetSeverityText("DEBUG")
 	record.SetBody(log.StringValue(e.Signal().Description()))
 
-	// Add signal identifier
-	record.AddAttributes(log.String("aperture.signal", e.Signal().Name()))
+	// Add signal identifier and description
+	record.AddAttributes(
+		log.String("aperture.signal", e.Signal().Name()),
+		log.String("aperture.signal.description", e.Signal().Description()),
+	)
 
 	// Convert fields directly (hardcoded string fields only)
 	for _, f := range e.Fields() {
@@ -106,7 +277,16 @@ func (io *internalObserver) handleEvent(ctx context.Context, e *capitan.Event) {
 		}
 	}
 
-	io.logger.Emit(ctx, record)
+	(*io.logger.Load()).Emit(ctx, record)
+
+	if fn := io.callback.Load(); fn != nil {
+		(*fn)(e.Signal(), e.Fields())
+	}
+}
+
+// setLogger replaces the logger diagnostics are written to.
+func (io *internalObserver) setLogger(logger log.Logger) {
+	io.logger.Store(&logger)
 }
 
 // emit emits an internal diagnostic event.
@@ -114,6 +294,14 @@ func (io *internalObserver) emit(ctx context.Context, signal capitan.Signal, fie
 	io.capitan.Emit(ctx, signal, fields...)
 }
 
+// Drain blocks until all queued diagnostic events have been processed.
+func (io *internalObserver) Drain(ctx context.Context) error {
+	if io.observer != nil {
+		return io.observer.Drain(ctx)
+	}
+	return nil
+}
+
 // Close stops the internal observer.
 func (io *internalObserver) Close() {
 	if io.observer != nil {
@@ -2,14 +2,19 @@ package aperture
 
 import (
 	"context"
+	"math"
 	"sync"
 	"testing"
 	"time"
 
+	apertesting "github.com/zoobzio/aperture/testing"
 	"github.com/zoobzio/capitan"
 	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/log/embedded"
 	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	tracenoop "go.opentelemetry.io/otel/trace/noop"
 )
 
@@ -79,6 +84,28 @@ func (m *mockLogger) waitForRecords(n int, timeout time.Duration) []log.Record {
 	return result
 }
 
+// waitForSignal blocks until a record with the given aperture.signal attribute
+// has been received or timeout expires. Returns nil on timeout.
+func (m *mockLogger) waitForSignal(signalName string, timeout time.Duration) *log.Record {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		if record := findRecordWithSignal(m.getRecords(), signalName); record != nil {
+			return record
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil
+		}
+
+		select {
+		case <-m.notify:
+		case <-time.After(remaining):
+		}
+	}
+}
+
 func (m *mockLogger) getRecords() []log.Record {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -242,6 +269,212 @@ func TestMetricValueMissing_EmittedOnMissingValue(t *testing.T) {
 	}
 }
 
+func TestMetricValueInvalid_EmittedOnUnparseableString(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	mockLog := newMockLogger()
+	provider := &mockLoggerProvider{logger: mockLog}
+
+	sh, err := New(cap, provider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{
+				Signal:           "payment.received",
+				Name:             "payment_amount",
+				Type:             "histogram",
+				ValueKey:         "amount",
+				ParseStringValue: true,
+			},
+		},
+	}
+	err = sh.Apply(schema)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	paymentReceived := capitan.NewSignal("payment.received", "Payment received")
+	amountKey := capitan.NewStringKey("amount")
+	cap.Emit(ctx, paymentReceived, amountKey.Field("forty-two"))
+
+	// Wait for records - main log + diagnostic
+	records := mockLog.waitForRecords(2, 2*time.Second)
+
+	if findRecordWithSignal(records, SignalMetricValueMissing.Name()) != nil {
+		t.Error("did not expect SignalMetricValueMissing for a present but invalid value")
+	}
+
+	record := findRecordWithSignal(records, SignalMetricValueInvalid.Name())
+	if record == nil {
+		t.Fatal("expected SignalMetricValueInvalid to be emitted for an unparseable string")
+	}
+	if v := getAttributeValue(record, "metric_name"); v != "payment_amount" {
+		t.Errorf("expected metric_name = 'payment_amount', got %q", v)
+	}
+	if v := getAttributeValue(record, "value_key"); v != "amount" {
+		t.Errorf("expected value_key = 'amount', got %q", v)
+	}
+	if v := getAttributeValue(record, "raw_value"); v != "forty-two" {
+		t.Errorf("expected raw_value = 'forty-two', got %q", v)
+	}
+}
+
+func TestMetricFilterMissing_Emitted(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	logProvider := apertesting.NewMockLoggerProvider()
+
+	sh, err := New(cap, logProvider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{Signal: "order.completed", Name: "successful_orders_total", FilterKey: "success"},
+		},
+	}
+	err = sh.Apply(schema)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	orderCompleted := capitan.NewSignal("order.completed", "Order completed")
+	cap.Emit(ctx, orderCompleted)
+
+	record := logProvider.Capture().WaitForSignal(SignalMetricFilterMissing.Name(), 2*time.Second)
+	if record == nil {
+		t.Fatal("expected SignalMetricFilterMissing to be emitted for an event without the filter field")
+	}
+	attrs := apertesting.LogRecordAttributes(*record)
+	if v := attrs["metric_name"]; v != "successful_orders_total" {
+		t.Errorf("expected metric_name = 'successful_orders_total', got %v", v)
+	}
+	if v := attrs["filter_key"]; v != "success" {
+		t.Errorf("expected filter_key = 'success', got %v", v)
+	}
+}
+
+func TestOnDiagnostic(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	mockLog := newMockLogger()
+	provider := &mockLoggerProvider{logger: mockLog}
+
+	sh, err := New(cap, provider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	type diagnostic struct {
+		signal string
+		fields map[string]any
+	}
+	var mu sync.Mutex
+	var got []diagnostic
+	sh.OnDiagnostic(func(signal capitan.Signal, fields []capitan.Field) {
+		d := diagnostic{signal: signal.Name(), fields: make(map[string]any)}
+		for _, f := range fields {
+			d.fields[f.Key().Name()] = f.Value()
+		}
+		mu.Lock()
+		got = append(got, d)
+		mu.Unlock()
+	})
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{Signal: "test.metric.signal", Name: "test_gauge", Type: "gauge", ValueKey: "value"},
+		},
+	}
+	if err := sh.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	testSignal := capitan.NewSignal("test.metric.signal", "Test metric signal")
+	cap.Emit(ctx, testSignal)
+
+	// The diagnostic is still logged
+	records := mockLog.waitForRecords(2, 2*time.Second)
+	if findRecordWithSignal(records, SignalMetricValueMissing.Name()) == nil {
+		t.Fatal("expected SignalMetricValueMissing to be logged")
+	}
+	if err := sh.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	mu.Lock()
+	if len(got) != 1 {
+		mu.Unlock()
+		t.Fatalf("expected 1 callback, got %d", len(got))
+	}
+	d := got[0]
+	mu.Unlock()
+	if d.signal != SignalMetricValueMissing.Name() {
+		t.Errorf("signal = %q, want %q", d.signal, SignalMetricValueMissing.Name())
+	}
+	if d.fields["metric_name"] != "test_gauge" {
+		t.Errorf("metric_name = %v, want test_gauge", d.fields["metric_name"])
+	}
+
+	// Removing the callback stops further calls
+	sh.OnDiagnostic(nil)
+	cap.Emit(ctx, testSignal)
+	mockLog.waitForRecords(4, 2*time.Second)
+	if err := sh.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 1 {
+		t.Errorf("expected no callbacks after OnDiagnostic(nil), got %d", len(got))
+	}
+}
+
+func TestMetricValueMissing_StringValueWithoutParsing(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	mockLog := newMockLogger()
+	provider := &mockLoggerProvider{logger: mockLog}
+
+	sh, err := New(cap, provider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{Signal: "payment.received", Name: "payment_amount", Type: "histogram", ValueKey: "amount"},
+		},
+	}
+	err = sh.Apply(schema)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	paymentReceived := capitan.NewSignal("payment.received", "Payment received")
+	amountKey := capitan.NewStringKey("amount")
+	cap.Emit(ctx, paymentReceived, amountKey.Field("42.5"))
+
+	// Default off: string fields are treated as missing
+	records := mockLog.waitForRecords(2, 2*time.Second)
+	if findRecordWithSignal(records, SignalMetricValueMissing.Name()) == nil {
+		t.Error("expected SignalMetricValueMissing when string parsing is disabled")
+	}
+}
+
 func TestMetricValueMissing_NotEmittedWhenValuePresent(t *testing.T) {
 	ctx := context.Background()
 	cap := capitan.New()
@@ -321,18 +554,21 @@ func TestTraceExpired_EmittedOnTimeout(t *testing.T) {
 		t.Fatalf("Apply failed: %v", err)
 	}
 
+	th := sh.capitanObserver.tracesHandler
+	clock := useFakeClock(th)
+
 	// Emit only the start event (no matching end)
 	cap.Emit(ctx, startSignal, correlationKey.Field("test-correlation-123"))
+	sh.Flush(ctx)
 
-	// Wait for cleanup to trigger (cleanup runs every 1 minute by default)
-	// We need to trigger it manually or wait - let's trigger manually
-	sh.capitanObserver.tracesHandler.cleanupStaleSpans()
-
-	// That won't work yet because the timeout hasn't passed - wait for it
-	time.Sleep(100 * time.Millisecond)
+	// Not expired before the timeout passes
+	th.cleanupStaleSpans()
+	if starts, _ := sh.PendingSpanCount(); starts != 1 {
+		t.Fatalf("expected start to stay pending before timeout, got %d", starts)
+	}
 
-	// Now trigger cleanup again
-	sh.capitanObserver.tracesHandler.cleanupStaleSpans()
+	clock.Advance(100 * time.Millisecond)
+	th.cleanupStaleSpans()
 
 	// Wait for diagnostic record - at least 2: main log + diagnostic
 	records := mockLog.waitForRecords(2, 2*time.Second)
@@ -388,12 +624,15 @@ func TestTraceExpired_PendingEnd(t *testing.T) {
 		t.Fatalf("Apply failed: %v", err)
 	}
 
+	th := sh.capitanObserver.tracesHandler
+	clock := useFakeClock(th)
+
 	// Emit only the END event (no matching start - out of order arrival that never completes)
 	cap.Emit(ctx, endSignal, correlationKey.Field("orphan-end-456"))
+	sh.Flush(ctx)
 
-	// Wait for timeout
-	time.Sleep(100 * time.Millisecond)
-	sh.capitanObserver.tracesHandler.cleanupStaleSpans()
+	clock.Advance(100 * time.Millisecond)
+	th.cleanupStaleSpans()
 
 	// Wait for records - at least 2: main log + diagnostic
 	records := mockLog.waitForRecords(2, 2*time.Second)
@@ -441,16 +680,17 @@ func TestTraceExpired_NotEmittedWhenMatched(t *testing.T) {
 		t.Fatalf("Apply failed: %v", err)
 	}
 
+	th := sh.capitanObserver.tracesHandler
+	clock := useFakeClock(th)
+
 	// Emit matching start and end events
 	cap.Emit(ctx, startSignal, correlationKey.Field("matched-789"))
 	cap.Emit(ctx, endSignal, correlationKey.Field("matched-789"))
+	sh.Flush(ctx)
 
-	// Wait and trigger cleanup
-	time.Sleep(100 * time.Millisecond)
-	sh.capitanObserver.tracesHandler.cleanupStaleSpans()
-
-	// Brief wait
-	time.Sleep(50 * time.Millisecond)
+	clock.Advance(100 * time.Millisecond)
+	th.cleanupStaleSpans()
+	sh.Flush(ctx)
 
 	// Should NOT have any trace expired diagnostics
 	records := mockLog.getRecords()
@@ -712,6 +952,834 @@ func TestTraceCorrelationMissing_NotEmittedWhenPresent(t *testing.T) {
 	}
 }
 
+func TestTraceDuplicateStart_KeepsEarliest(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	mockLog := newMockLogger()
+	provider := &mockLoggerProvider{logger: mockLog}
+
+	recorder := tracetest.NewSpanRecorder()
+	traceProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer traceProvider.Shutdown(ctx)
+
+	startSignal := capitan.NewSignal("test.span.start", "Span start")
+	endSignal := capitan.NewSignal("test.span.end", "Span end")
+	correlationKey := capitan.NewStringKey("trace_id")
+
+	sh, err := New(cap, provider, metricnoop.NewMeterProvider(), traceProvider)
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	schema := Schema{
+		Traces: []TraceSchema{
+			{
+				Start:          "test.span.start",
+				End:            "test.span.end",
+				CorrelationKey: "trace_id",
+				SpanName:       "test-span",
+			},
+		},
+	}
+	err = sh.Apply(schema)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	// Two starts (a retry) followed by one end. Signals are handled by
+	// separate workers, so the end is emitted only once the second start has
+	// been seen as a duplicate.
+	cap.Emit(ctx, startSignal, correlationKey.Field("retry-id"))
+	time.Sleep(10 * time.Millisecond)
+	firstStartDone := time.Now()
+	cap.Emit(ctx, startSignal, correlationKey.Field("retry-id"))
+
+	record := mockLog.waitForSignal(SignalTraceDuplicateStart.Name(), 2*time.Second)
+	if record == nil {
+		t.Fatal("expected SignalTraceDuplicateStart to be emitted for the second start")
+	}
+	if starts, _ := sh.PendingSpanCount(); starts != 1 {
+		t.Fatalf("expected 1 pending start, got %d", starts)
+	}
+
+	cap.Emit(ctx, endSignal, correlationKey.Field("retry-id"))
+	sh.Flush(ctx)
+
+	if v := getAttributeValue(record, "correlation_id"); v != "retry-id" {
+		t.Errorf("expected correlation_id = 'retry-id', got %q", v)
+	}
+	if v := getAttributeValue(record, "span_name"); v != "test-span" {
+		t.Errorf("expected span_name = 'test-span', got %q", v)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected exactly 1 span, got %d", len(spans))
+	}
+	if !spans[0].StartTime().Before(firstStartDone) {
+		t.Error("expected span to start at the earliest start event")
+	}
+}
+
+func TestTraceDuplicateEnd_FirstWins(t *testing.T) {
+	ctx := context.Background()
+	// Each signal has its own worker, so only sync mode keeps the order the
+	// start and end events are emitted in
+	cap := capitan.New(capitan.WithSyncMode())
+
+	mockLog := newMockLogger()
+	provider := &mockLoggerProvider{logger: mockLog}
+
+	recorder := tracetest.NewSpanRecorder()
+	traceProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer traceProvider.Shutdown(ctx)
+
+	started := capitan.NewSignal("request.started", "Request started")
+	completed := capitan.NewSignal("request.completed", "Request completed")
+	failed := capitan.NewSignal("request.failed", "Request failed")
+	requestID := capitan.NewStringKey("request_id")
+
+	sh, err := New(cap, provider, metricnoop.NewMeterProvider(), traceProvider)
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	schema := Schema{
+		Traces: []TraceSchema{
+			{
+				Start:          "request.started",
+				Ends:           []string{"request.completed", "request.failed"},
+				CorrelationKey: "request_id",
+				SpanName:       "request",
+			},
+		},
+	}
+	if err := sh.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	// Start first: failed closes the span, the later completed is a duplicate
+	cap.Emit(ctx, started, requestID.Field("in-order"))
+	cap.Emit(ctx, failed, requestID.Field("in-order"))
+	time.Sleep(10 * time.Millisecond)
+	secondEndAt := time.Now()
+	cap.Emit(ctx, completed, requestID.Field("in-order"))
+
+	// Ends first: completed is kept, failed is a duplicate, start pairs with completed
+	cap.Emit(ctx, completed, requestID.Field("ends-first"))
+	cap.Emit(ctx, failed, requestID.Field("ends-first"))
+	cap.Emit(ctx, started, requestID.Field("ends-first"))
+
+	if err := sh.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	records := mockLog.waitForRecords(8, 2*time.Second)
+
+	duplicates := make(map[string]string)
+	for i := range records {
+		if getAttributeValue(&records[i], "aperture.signal") == SignalTraceDuplicateEnd.Name() {
+			duplicates[getAttributeValue(&records[i], "correlation_id")] = getAttributeValue(&records[i], "signal")
+		}
+	}
+	want := map[string]string{"in-order": "request.completed", "ends-first": "request.failed"}
+	for id, signal := range want {
+		if duplicates[id] != signal {
+			t.Errorf("duplicate end for %s = %q, want %q", id, duplicates[id], signal)
+		}
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	for _, span := range spans {
+		if span.Name() != "request" {
+			t.Errorf("span name = %q, want request", span.Name())
+		}
+	}
+
+	// The in-order span ends at the first end, not the duplicate
+	if !spans[0].EndTime().Before(secondEndAt) {
+		t.Errorf("span end = %v, want before the duplicate end at %v", spans[0].EndTime(), secondEndAt)
+	}
+
+	// The in-order marker was consumed by its duplicate; the ends-first span
+	// keeps a closed-span marker until its timeout, and no end is left waiting
+	if starts, ends := sh.PendingSpanCount(); starts != 1 || ends != 0 {
+		t.Errorf("PendingSpanCount() = %d, %d, want 1, 0", starts, ends)
+	}
+}
+
+func TestTraceNegativeDuration_ClampsToZero(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	mockLog := newMockLogger()
+	provider := &mockLoggerProvider{logger: mockLog}
+
+	recorder := tracetest.NewSpanRecorder()
+	traceProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer traceProvider.Shutdown(ctx)
+
+	startSignal := capitan.NewSignal("test.span.start", "Span start")
+	endSignal := capitan.NewSignal("test.span.end", "Span end")
+	correlationKey := capitan.NewStringKey("trace_id")
+
+	sh, err := New(cap, provider, metricnoop.NewMeterProvider(), traceProvider)
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	schema := Schema{
+		Traces: []TraceSchema{
+			{
+				Start:          "test.span.start",
+				End:            "test.span.end",
+				CorrelationKey: "trace_id",
+				SpanName:       "test-span",
+			},
+		},
+	}
+	err = sh.Apply(schema)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	// End is emitted (and timestamped) before start
+	cap.Emit(ctx, endSignal, correlationKey.Field("skewed-id"))
+	time.Sleep(10 * time.Millisecond)
+	cap.Emit(ctx, startSignal, correlationKey.Field("skewed-id"))
+
+	// Wait for the diagnostic by name; other diagnostics, such as a missing
+	// service.name, can arrive first
+	record := mockLog.waitForSignal(SignalTraceNegativeDuration.Name(), 2*time.Second)
+	if record == nil {
+		t.Fatal("expected SignalTraceNegativeDuration to be emitted")
+	}
+	sh.Flush(ctx)
+
+	if v := getAttributeValue(record, "correlation_id"); v != "skewed-id" {
+		t.Errorf("expected correlation_id = 'skewed-id', got %q", v)
+	}
+	if v := getAttributeValue(record, "span_name"); v != "test-span" {
+		t.Errorf("expected span_name = 'test-span', got %q", v)
+	}
+	if skew, err := time.ParseDuration(getAttributeValue(record, "skew")); err != nil || skew <= 0 {
+		t.Errorf("expected a positive skew, got %q", getAttributeValue(record, "skew"))
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected exactly 1 span, got %d", len(spans))
+	}
+	if !spans[0].EndTime().Equal(spans[0].StartTime()) {
+		t.Errorf("expected zero duration, got %v", spans[0].EndTime().Sub(spans[0].StartTime()))
+	}
+}
+
+func TestTraceNegativeDuration_NotEmittedInOrder(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	mockLog := newMockLogger()
+	provider := &mockLoggerProvider{logger: mockLog}
+
+	sh, err := New(cap, provider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(Schema{
+		Traces: []TraceSchema{
+			{Start: "test.span.start", End: "test.span.end", CorrelationKey: "trace_id"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	startSignal := capitan.NewSignal("test.span.start", "Span start")
+	endSignal := capitan.NewSignal("test.span.end", "Span end")
+	correlationKey := capitan.NewStringKey("trace_id")
+
+	cap.Emit(ctx, startSignal, correlationKey.Field("ordered-id"))
+	time.Sleep(10 * time.Millisecond)
+	cap.Emit(ctx, endSignal, correlationKey.Field("ordered-id"))
+
+	time.Sleep(100 * time.Millisecond)
+
+	if record := findRecordWithSignal(mockLog.getRecords(), SignalTraceNegativeDuration.Name()); record != nil {
+		t.Error("did not expect SignalTraceNegativeDuration for an in-order pair")
+	}
+}
+
+func TestTracePendingOverflow(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	mockLog := newMockLogger()
+	provider := &mockLoggerProvider{logger: mockLog}
+
+	recorder := tracetest.NewSpanRecorder()
+	traceProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer traceProvider.Shutdown(ctx)
+
+	sh, err := New(cap, provider, metricnoop.NewMeterProvider(), traceProvider)
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(Schema{
+		MaxPending: 2,
+		Logs:       &LogSchema{Whitelist: []string{"none"}},
+		Traces: []TraceSchema{
+			{Start: "test.span.start", End: "test.span.end", CorrelationKey: "trace_id", SpanName: "test-span"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	startSignal := capitan.NewSignal("test.span.start", "Span start")
+	endSignal := capitan.NewSignal("test.span.end", "Span end")
+	correlationKey := capitan.NewStringKey("trace_id")
+
+	cap.Emit(ctx, startSignal, correlationKey.Field("a"))
+	cap.Emit(ctx, startSignal, correlationKey.Field("b"))
+	cap.Emit(ctx, startSignal, correlationKey.Field("c")) // rejected
+
+	records := mockLog.waitForRecords(1, 2*time.Second)
+
+	record := findRecordWithSignal(records, SignalTracePendingOverflow.Name())
+	if record == nil {
+		t.Fatal("expected SignalTracePendingOverflow to be emitted")
+	}
+	if v := getAttributeValue(record, "correlation_id"); v != "c" {
+		t.Errorf("expected correlation_id = 'c', got %q", v)
+	}
+	if v := getAttributeValue(record, "span_name"); v != "test-span" {
+		t.Errorf("expected span_name = 'test-span', got %q", v)
+	}
+	if v := getAttributeValue(record, "max_pending"); v != "2" {
+		t.Errorf("expected max_pending = '2', got %q", v)
+	}
+
+	if starts, _ := sh.PendingSpanCount(); starts != 2 {
+		t.Errorf("expected 2 pending starts, got %d", starts)
+	}
+
+	// Pending starts still complete; the rejected one does not
+	cap.Emit(ctx, endSignal, correlationKey.Field("a"))
+	cap.Emit(ctx, endSignal, correlationKey.Field("c"))
+	time.Sleep(100 * time.Millisecond)
+
+	if spans := recorder.Ended(); len(spans) != 1 {
+		t.Errorf("expected 1 span, got %d", len(spans))
+	}
+	if starts, ends := sh.PendingSpanCount(); starts != 1 || ends != 1 {
+		t.Errorf("expected 1 pending start and 1 pending end, got %d/%d", starts, ends)
+	}
+}
+
+func TestConfigError_BestEffort(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	mockLog := newMockLogger()
+	provider := &mockLoggerProvider{logger: mockLog}
+
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(sdkmetric.NewManualReader()))
+	defer meterProvider.Shutdown(ctx)
+
+	sh, err := New(cap, provider, meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	// The SDK rejects the second instrument name
+	err = sh.Apply(Schema{
+		BestEffort: true,
+		Metrics: []MetricSchema{
+			{Signal: "order.created", Name: "orders_total", Type: "counter"},
+			{Signal: "order.failed", Name: "1 invalid name", Type: "counter"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected best-effort Apply to succeed, got %v", err)
+	}
+
+	if names := sh.MetricNames(); len(names) != 1 || names[0] != "orders_total" {
+		t.Errorf("MetricNames() = %v, want [orders_total]", names)
+	}
+
+	records := mockLog.waitForRecords(1, 2*time.Second)
+
+	record := findRecordWithSignal(records, SignalConfigError.Name())
+	if record == nil {
+		t.Fatal("expected SignalConfigError to be emitted")
+	}
+	if v := getAttributeValue(record, "signal"); v != "order.failed" {
+		t.Errorf("expected signal = 'order.failed', got %q", v)
+	}
+	if v := getAttributeValue(record, "metric_name"); v != "1 invalid name" {
+		t.Errorf("expected metric_name = '1 invalid name', got %q", v)
+	}
+	if v := getAttributeValue(record, "reason"); v == "" {
+		t.Error("expected a non-empty reason")
+	}
+}
+
+func TestFindClampedFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []capitan.Field
+		want   []clampedField
+	}{
+		{
+			name:   "no fields",
+			fields: nil,
+			want:   nil,
+		},
+		{
+			name: "uint64 within range",
+			fields: []capitan.Field{
+				capitan.NewUint64Key("bytes").Field(uint64(math.MaxInt64)),
+			},
+			want: nil,
+		},
+		{
+			name: "uint64 exceeds max int64",
+			fields: []capitan.Field{
+				capitan.NewUint64Key("bytes").Field(uint64(math.MaxUint64)),
+			},
+			want: []clampedField{{key: "bytes", original: math.MaxUint64}},
+		},
+		{
+			name: "uint exceeds max int64",
+			fields: []capitan.Field{
+				capitan.NewUintKey("count").Field(uint(math.MaxInt64) + 1),
+			},
+			want: []clampedField{{key: "count", original: uint64(math.MaxInt64) + 1}},
+		},
+		{
+			name: "uint32 never clamps",
+			fields: []capitan.Field{
+				capitan.NewUint32Key("small").Field(uint32(math.MaxUint32)),
+			},
+			want: nil,
+		},
+		{
+			name: "only out-of-range fields reported",
+			fields: []capitan.Field{
+				capitan.NewStringKey("name").Field("x"),
+				capitan.NewUint64Key("ok").Field(uint64(1)),
+				capitan.NewUint64Key("big").Field(uint64(math.MaxInt64) + 5),
+			},
+			want: []clampedField{{key: "big", original: uint64(math.MaxInt64) + 5}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findClampedFields(tt.fields)
+			if len(got) != len(tt.want) {
+				t.Fatalf("findClampedFields() = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("findClampedFields()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValueClamped_EmittedOnOverflow(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	mockLog := newMockLogger()
+	provider := &mockLoggerProvider{logger: mockLog}
+
+	sh, err := New(cap, provider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(Schema{
+		Metrics: []MetricSchema{
+			{Signal: "disk.usage", Name: "disk_bytes", Type: "gauge", ValueKey: "bytes"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	diskUsage := capitan.NewSignal("disk.usage", "Disk usage")
+	bytesKey := capitan.NewUint64Key("bytes")
+
+	cap.Emit(ctx, diskUsage, bytesKey.Field(uint64(math.MaxUint64)))
+
+	// Wait for records - main log + diagnostic
+	records := mockLog.waitForRecords(2, 2*time.Second)
+
+	record := findRecordWithSignal(records, SignalValueClamped.Name())
+	if record == nil {
+		t.Fatal("expected SignalValueClamped to be emitted for an out-of-range uint64")
+	}
+	if v := getAttributeValue(record, "signal"); v != "disk.usage" {
+		t.Errorf("expected signal = 'disk.usage', got %q", v)
+	}
+	if v := getAttributeValue(record, "field_key"); v != "bytes" {
+		t.Errorf("expected field_key = 'bytes', got %q", v)
+	}
+	if v := getAttributeValue(record, "original_value"); v != "18446744073709551615" {
+		t.Errorf("expected original_value = '18446744073709551615', got %q", v)
+	}
+
+	// Exactly one diagnostic per clamped field, even though both log and metric transforms clamp it
+	count := 0
+	for i := range records {
+		if findRecordWithSignal(records[i:i+1], SignalValueClamped.Name()) != nil {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected 1 SignalValueClamped record, got %d", count)
+	}
+}
+
+func TestTransformFailed_Emitted(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	mockLog := newMockLogger()
+	provider := &mockLoggerProvider{logger: mockLog}
+
+	sh, err := New(cap, provider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	if err := sh.Apply(Schema{KeepUnserializable: true}); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	jobQueued := capitan.NewSignal("job.queued", "Job queued")
+	doneKey := capitan.NewKey[chan struct{}]("done", "test.Done")
+
+	cap.Emit(ctx, jobQueued, doneKey.Field(make(chan struct{})))
+
+	// Wait for records - main log + diagnostic
+	records := mockLog.waitForRecords(2, 2*time.Second)
+
+	record := findRecordWithSignal(records, SignalTransformFailed.Name())
+	if record == nil {
+		t.Fatal("expected SignalTransformFailed to be emitted for a channel field")
+	}
+	if v := getAttributeValue(record, "signal"); v != "job.queued" {
+		t.Errorf("expected signal = 'job.queued', got %q", v)
+	}
+	if v := getAttributeValue(record, "field_key"); v != "done" {
+		t.Errorf("expected field_key = 'done', got %q", v)
+	}
+	if v := getAttributeValue(record, "reason"); v == "" {
+		t.Error("expected a non-empty reason")
+	}
+
+	// The event's own log keeps the field as a placeholder
+	event := findRecordWithSignal(records, "job.queued")
+	if event == nil {
+		t.Fatal("expected the job.queued log record")
+	}
+	if v := getAttributeValue(event, "done"); v != "<unserializable:chan struct {}>" {
+		t.Errorf("expected placeholder for done, got %q", v)
+	}
+}
+
+func TestValueClamped_NotEmittedWhenFiltered(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	mockLog := newMockLogger()
+	provider := &mockLoggerProvider{logger: mockLog}
+
+	sh, err := New(cap, provider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	// Signal is neither logged nor measured, so nothing is transformed
+	err = sh.Apply(Schema{
+		Logs: &LogSchema{Whitelist: []string{"other.signal"}},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	diskUsage := capitan.NewSignal("disk.usage", "Disk usage")
+	bytesKey := capitan.NewUint64Key("bytes")
+
+	cap.Emit(ctx, diskUsage, bytesKey.Field(uint64(math.MaxUint64)))
+	time.Sleep(50 * time.Millisecond)
+
+	if record := findRecordWithSignal(mockLog.getRecords(), SignalValueClamped.Name()); record != nil {
+		t.Error("did not expect SignalValueClamped for an untransformed event")
+	}
+}
+
+func TestConfigUnused_EmittedForUnseenSignals(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	mockLog := newMockLogger()
+	provider := &mockLoggerProvider{logger: mockLog}
+
+	sh, err := New(cap, provider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(Schema{
+		Metrics: []MetricSchema{
+			{Signal: "order.created", Name: "orders_total"},
+			{Signal: "order.craeted", Name: "orders_typo_total"},
+		},
+		Traces: []TraceSchema{
+			{Start: "request.started", End: "request.completed", CorrelationKey: "request_id", SpanName: "http_request"},
+		},
+		UnusedConfigWarmup: "100ms",
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	orderCreated := capitan.NewSignal("order.created", "Order created")
+	requestStarted := capitan.NewSignal("request.started", "Request started")
+	requestIDKey := capitan.NewStringKey("request_id")
+
+	cap.Emit(ctx, orderCreated)
+	cap.Emit(ctx, requestStarted, requestIDKey.Field("REQ-1"))
+
+	time.Sleep(300 * time.Millisecond)
+
+	unused := make(map[string]*log.Record)
+	records := mockLog.getRecords()
+	for i := range records {
+		if r := findRecordWithSignal(records[i:i+1], SignalConfigUnused.Name()); r != nil {
+			unused[getAttributeValue(r, "signal")] = r
+		}
+	}
+
+	if len(unused) != 2 {
+		t.Fatalf("expected 2 unused config diagnostics, got %d", len(unused))
+	}
+	if r := unused["order.craeted"]; r == nil {
+		t.Error("expected diagnostic for misspelled metric signal")
+	} else if v := getAttributeValue(r, "metric_name"); v != "orders_typo_total" {
+		t.Errorf("expected metric_name = 'orders_typo_total', got %q", v)
+	}
+	if r := unused["request.completed"]; r == nil {
+		t.Error("expected diagnostic for unseen trace end signal")
+	} else if v := getAttributeValue(r, "span_name"); v != "http_request" {
+		t.Errorf("expected span_name = 'http_request', got %q", v)
+	}
+}
+
+func TestConfigUnused_DisabledByDefault(t *testing.T) {
+	cap := capitan.New()
+
+	mockLog := newMockLogger()
+	provider := &mockLoggerProvider{logger: mockLog}
+
+	sh, err := New(cap, provider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(Schema{
+		Metrics: []MetricSchema{{Signal: "never.emitted", Name: "never_total"}},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if sh.capitanObserver.unused != nil {
+		t.Error("expected no unused tracker without unused_config_warmup")
+	}
+}
+
+func TestConfigUnused_StoppedOnApply(t *testing.T) {
+	cap := capitan.New()
+
+	mockLog := newMockLogger()
+	provider := &mockLoggerProvider{logger: mockLog}
+
+	sh, err := New(cap, provider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(Schema{
+		Metrics:            []MetricSchema{{Signal: "never.emitted", Name: "never_total"}},
+		UnusedConfigWarmup: "100ms",
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	// Replacing the config before the warmup elapses cancels the report
+	if err := sh.Apply(Schema{}); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	if findRecordWithSignal(mockLog.getRecords(), SignalConfigUnused.Name()) != nil {
+		t.Error("did not expect SignalConfigUnused after config was replaced")
+	}
+}
+
+func TestServiceNameMissing(t *testing.T) {
+	named, err := WithServiceInfo("checkout", "v1.0.0")
+	if err != nil {
+		t.Fatalf("WithServiceInfo failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     []sdktrace.TracerProviderOption
+		wantDiag bool
+	}{
+		{"sdk default resource", nil, true},
+		{"service info set", []sdktrace.TracerProviderOption{sdktrace.WithResource(named)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cap := capitan.New()
+
+			mockLog := newMockLogger()
+			provider := &mockLoggerProvider{logger: mockLog}
+
+			traceProvider := sdktrace.NewTracerProvider(tt.opts...)
+			defer traceProvider.Shutdown(ctx)
+
+			sh, err := New(cap, provider, metricnoop.NewMeterProvider(), traceProvider)
+			if err != nil {
+				t.Fatalf("failed to create Aperture: %v", err)
+			}
+			defer sh.Close()
+
+			err = sh.Apply(Schema{
+				Traces: []TraceSchema{
+					{Start: "test.span.start", End: "test.span.end", CorrelationKey: "trace_id"},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+
+			startSignal := capitan.NewSignal("test.span.start", "Span start")
+			endSignal := capitan.NewSignal("test.span.end", "Span end")
+			correlationKey := capitan.NewStringKey("trace_id")
+
+			// Two spans - the diagnostic must only be emitted once
+			for _, id := range []string{"a", "b"} {
+				cap.Emit(ctx, startSignal, correlationKey.Field(id))
+				cap.Emit(ctx, endSignal, correlationKey.Field(id))
+			}
+
+			time.Sleep(100 * time.Millisecond)
+
+			count := 0
+			records := mockLog.getRecords()
+			for i := range records {
+				if findRecordWithSignal(records[i:i+1], SignalServiceNameMissing.Name()) != nil {
+					count++
+				}
+			}
+
+			switch {
+			case tt.wantDiag && count != 1:
+				t.Errorf("expected 1 SignalServiceNameMissing record, got %d", count)
+			case !tt.wantDiag && count != 0:
+				t.Errorf("expected no SignalServiceNameMissing records, got %d", count)
+			}
+		})
+	}
+}
+
+func TestMetricHistogramTypeChanged_EmittedOnApply(t *testing.T) {
+	cap := capitan.New()
+
+	mockLog := newMockLogger()
+	provider := &mockLoggerProvider{logger: mockLog}
+
+	sh, err := New(cap, provider, metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{Signal: "request.completed", Name: "request_size", Type: "histogram", ValueKey: "size"},
+			{Signal: "request.completed", Name: "request_duration", Type: "histogram", ValueKey: "duration"},
+		},
+	}
+	if err := sh.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	// An explicit type is the default, so only request_duration changes.
+	// Diagnostics are delivered in order, so a wrong one for request_size
+	// would arrive first.
+	schema.Metrics[0].HistogramType = "explicit"
+	schema.Metrics[1].HistogramType = "exponential"
+	if err := sh.Apply(schema); err != nil {
+		t.Fatalf("second Apply failed: %v", err)
+	}
+
+	record := mockLog.waitForSignal(SignalMetricHistogramTypeChanged.Name(), 2*time.Second)
+	if record == nil {
+		t.Fatal("expected SignalMetricHistogramTypeChanged to be emitted")
+	}
+	if v := getAttributeValue(record, "metric_name"); v != "request_duration" {
+		t.Errorf("expected metric_name = 'request_duration', got %q", v)
+	}
+	if v := getAttributeValue(record, "signal"); v != "request.completed" {
+		t.Errorf("expected signal = 'request.completed', got %q", v)
+	}
+	if v := getAttributeValue(record, "reason"); v != `histogram_type changed from "explicit" to "exponential"` {
+		t.Errorf("unexpected reason %q", v)
+	}
+
+	count := 0
+	for _, r := range mockLog.getRecords() {
+		if getAttributeValue(&r, "aperture.signal") == SignalMetricHistogramTypeChanged.Name() {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected 1 histogram_type_changed diagnostic, got %d", count)
+	}
+}
+
 func TestInternalSignals_Defined(t *testing.T) {
 	signals := []struct {
 		signal      capitan.Signal
@@ -720,7 +1788,19 @@ func TestInternalSignals_Defined(t *testing.T) {
 	}{
 		{SignalTraceExpired, "aperture:trace:expired", "pending span expired without matching start/end"},
 		{SignalMetricValueMissing, "aperture:metric:value_missing", "metric value could not be extracted from event"},
+		{SignalMetricValueInvalid, "aperture:metric:value_invalid", "metric string value could not be parsed as a number"},
+		{SignalMetricFilterMissing, "aperture:metric:filter_missing", "metric filter field missing from event"},
+		{SignalMetricHistogramTypeChanged, "aperture:metric:histogram_type_changed", "histogram_type changed; meter provider views still use the old aggregation"},
 		{SignalTraceCorrelationMissing, "aperture:trace:correlation_missing", "trace event missing correlation ID field"},
+		{SignalTraceDuplicateStart, "aperture:trace:duplicate_start", "duplicate start event for pending span"},
+		{SignalTraceDuplicateEnd, "aperture:trace:duplicate_end", "duplicate end event for span; first end wins"},
+		{SignalTraceNegativeDuration, "aperture:trace:negative_duration", "span end timestamp precedes start timestamp"},
+		{SignalTracePendingOverflow, "aperture:trace:pending_overflow", "pending span limit reached; event dropped"},
+		{SignalValueClamped, "aperture:value:clamped", "unsigned field value clamped to max int64"},
+		{SignalTransformFailed, "aperture:transform:failed", "custom field value could not be serialized"},
+		{SignalConfigError, "aperture:config:error", "metric instrument could not be created; metric skipped"},
+		{SignalConfigUnused, "aperture:config:unused", "configured signal not seen within warmup window"},
+		{SignalServiceNameMissing, "aperture:resource:service_name_missing", "trace provider resource lacks service.name"},
 	}
 
 	for _, s := range signals {
@@ -745,6 +1825,11 @@ func TestInternalFieldKeys_Defined(t *testing.T) {
 		{internalMetricName, "metric_name"},
 		{internalValueKey, "value_key"},
 		{internalCorrelationKey, "correlation_key"},
+		{internalFieldKey, "field_key"},
+		{internalOriginalValue, "original_value"},
+		{internalRawValue, "raw_value"},
+		{internalSkew, "skew"},
+		{internalMaxPending, "max_pending"},
 	}
 
 	for _, k := range keys {
@@ -0,0 +1,114 @@
+package aperture
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// schemaEnums lists the allowed values of enumerated string fields,
+// keyed by Go type name and JSON field name.
+var schemaEnums = map[string][]string{
+	"Schema.stdout_format":        {string(StdoutFormatText), string(StdoutFormatJSON)},
+	"MetricSchema.type":           {string(MetricTypeCounter), string(MetricTypeGauge), string(MetricTypeHistogram), string(MetricTypeUpDownCounter)},
+	"MetricSchema.duration_unit":  {string(DurationUnitMilliseconds), string(DurationUnitNanoseconds)},
+	"MetricSchema.aggregation":    {string(GaugeAggregationLast), string(GaugeAggregationMax), string(GaugeAggregationMin), string(GaugeAggregationSum)},
+	"MetricSchema.histogram_type": {string(HistogramTypeExplicit), string(HistogramTypeExponential)},
+	"MetricSchema.buckets_preset": slices.Sorted(maps.Keys(bucketPresets)),
+}
+
+// SchemaJSONSchema returns a JSON Schema (draft 2020-12) document describing
+// the configuration format accepted by [LoadSchemaFromYAML] and [LoadSchemaFromJSON].
+//
+// The document is generated from the json struct tags of [Schema] and its nested
+// types, so it stays in sync with the loaders. Fields without omitempty are
+// required, and unknown fields are rejected. Editors can use it to validate
+// YAML before loading.
+//
+// It describes structure and enumerated values only. Cross-field rules, such as
+// value_key being required for gauges, are enforced by [Schema.Validate].
+func SchemaJSONSchema() []byte {
+	return slices.Clone(schemaJSONSchema())
+}
+
+// schemaJSONSchema builds the document once; the types it describes are static.
+// SchemaJSONSchema returns a copy so callers cannot modify the cached bytes.
+var schemaJSONSchema = sync.OnceValue(func() []byte {
+	defs := make(map[string]any)
+	root := structJSONSchema(reflect.TypeOf(Schema{}), defs)
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = "aperture schema"
+	root["$defs"] = defs
+
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		panic("aperture: marshaling JSON schema: " + err.Error()) // only static maps of basic types
+	}
+	return data
+})
+
+// structJSONSchema describes a struct's json-tagged fields as an object schema.
+// Nested struct types are added to defs and referenced by name.
+func structJSONSchema(t reflect.Type, defs map[string]any) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "" || name == "-" {
+			continue
+		}
+
+		prop := typeJSONSchema(f.Type, defs)
+		if enum, ok := schemaEnums[t.Name()+"."+name]; ok {
+			prop["enum"] = enum
+		}
+		properties[name] = prop
+
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	s := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// typeJSONSchema describes a Go type as a JSON Schema fragment.
+func typeJSONSchema(t reflect.Type, defs map[string]any) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeJSONSchema(t.Elem(), defs)
+	case reflect.Struct:
+		if _, ok := defs[t.Name()]; !ok {
+			defs[t.Name()] = structJSONSchema(t, defs)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": typeJSONSchema(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeJSONSchema(t.Elem(), defs)}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	default:
+		return map[string]any{}
+	}
+}
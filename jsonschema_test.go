@@ -0,0 +1,144 @@
+package aperture
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// jsonSchemaDoc is the subset of the generated document inspected by tests.
+type jsonSchemaDoc struct {
+	Schema     string                     `json:"$schema"`
+	Properties map[string]json.RawMessage `json:"properties"`
+	Defs       map[string]jsonSchemaObj   `json:"$defs"`
+}
+
+type jsonSchemaObj struct {
+	Properties map[string]struct {
+		Type string   `json:"type"`
+		Enum []string `json:"enum"`
+		Ref  string   `json:"$ref"`
+	} `json:"properties"`
+	Required             []string `json:"required"`
+	AdditionalProperties bool     `json:"additionalProperties"`
+}
+
+func parseSchemaJSONSchema(t *testing.T) (jsonSchemaDoc, jsonSchemaObj) {
+	t.Helper()
+
+	data := SchemaJSONSchema()
+
+	var doc jsonSchemaDoc
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("SchemaJSONSchema is not valid JSON: %v", err)
+	}
+	var root jsonSchemaObj
+	if err := json.Unmarshal(data, &root); err != nil {
+		t.Fatalf("SchemaJSONSchema is not valid JSON: %v", err)
+	}
+	return doc, root
+}
+
+// jsonFieldNames returns the json tag names of a struct's fields.
+func jsonFieldNames(t reflect.Type) []string {
+	var names []string
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestSchemaJSONSchema_InSyncWithStructTags(t *testing.T) {
+	doc, root := parseSchemaJSONSchema(t)
+
+	if doc.Schema == "" {
+		t.Error("expected $schema to be set")
+	}
+
+	objects := map[reflect.Type]jsonSchemaObj{
+		reflect.TypeOf(Schema{}):        root,
+		reflect.TypeOf(MetricSchema{}):  doc.Defs["MetricSchema"],
+		reflect.TypeOf(TraceSchema{}):   doc.Defs["TraceSchema"],
+		reflect.TypeOf(LogSchema{}):     doc.Defs["LogSchema"],
+		reflect.TypeOf(ContextSchema{}): doc.Defs["ContextSchema"],
+	}
+
+	for typ, obj := range objects {
+		var got []string
+		for name := range obj.Properties {
+			got = append(got, name)
+		}
+		slices.Sort(got)
+
+		if want := jsonFieldNames(typ); !slices.Equal(got, want) {
+			t.Errorf("%s properties = %v, want %v", typ.Name(), got, want)
+		}
+	}
+}
+
+func TestSchemaJSONSchema_RequiredAndEnums(t *testing.T) {
+	doc, root := parseSchemaJSONSchema(t)
+
+	if len(root.Required) != 0 {
+		t.Errorf("Schema required = %v, want none", root.Required)
+	}
+	if root.Properties["metrics"].Type != "array" {
+		t.Errorf("metrics type = %q, want array", root.Properties["metrics"].Type)
+	}
+
+	metric := doc.Defs["MetricSchema"]
+	if want := []string{"signal", "name"}; !slices.Equal(metric.Required, want) {
+		t.Errorf("MetricSchema required = %v, want %v", metric.Required, want)
+	}
+	if want := []string{"counter", "gauge", "histogram", "updowncounter"}; !slices.Equal(metric.Properties["type"].Enum, want) {
+		t.Errorf("MetricSchema type enum = %v, want %v", metric.Properties["type"].Enum, want)
+	}
+	if want := []string{"bytes", "http_latency_ms", "latency_seconds"}; !slices.Equal(metric.Properties["buckets_preset"].Enum, want) {
+		t.Errorf("MetricSchema buckets_preset enum = %v, want %v", metric.Properties["buckets_preset"].Enum, want)
+	}
+	if metric.AdditionalProperties {
+		t.Error("expected unknown MetricSchema fields to be rejected")
+	}
+
+	trace := doc.Defs["TraceSchema"]
+	if want := []string{"start", "correlation_key"}; !slices.Equal(trace.Required, want) {
+		t.Errorf("TraceSchema required = %v, want %v", trace.Required, want)
+	}
+	if trace.Properties["sample_rate"].Type != "number" {
+		t.Errorf("sample_rate type = %q, want number", trace.Properties["sample_rate"].Type)
+	}
+}
+
+func TestSchemaEnums_ReferenceExistingFields(t *testing.T) {
+	types := map[string]reflect.Type{
+		"Schema":       reflect.TypeOf(Schema{}),
+		"MetricSchema": reflect.TypeOf(MetricSchema{}),
+		"TraceSchema":  reflect.TypeOf(TraceSchema{}),
+	}
+
+	for key := range schemaEnums {
+		typeName, field, _ := strings.Cut(key, ".")
+		typ, ok := types[typeName]
+		if !ok {
+			t.Errorf("schemaEnums key %q: unknown type %q", key, typeName)
+			continue
+		}
+		if !slices.Contains(jsonFieldNames(typ), field) {
+			t.Errorf("schemaEnums key %q: %s has no field %q", key, typeName, field)
+		}
+	}
+}
+
+func TestSchemaJSONSchema_ReturnsCopy(t *testing.T) {
+	a := SchemaJSONSchema()
+	a[0] = 'x'
+	if b := SchemaJSONSchema(); b[0] == 'x' {
+		t.Error("modifying the returned document changed the cached copy")
+	}
+}
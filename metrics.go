@@ -3,9 +3,15 @@ package aperture
 import (
 	"context"
 	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/zoobzio/capitan"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 )
 
@@ -22,14 +28,36 @@ type metricInstrument struct {
 	int64Histogram       metric.Int64Histogram
 	float64Histogram     metric.Float64Histogram
 
+	// staticAttrs are the configured constant attributes, converted once at creation.
+	staticAttrs []attribute.KeyValue
+
+	// allowedFields is the set of event field keys kept as dimensions (nil = all).
+	allowedFields map[string]struct{}
+
+	// aggregator is set for gauges using a non-"last" aggregation.
+	// Values are accumulated here and reported by an observable gauge callback.
+	aggregator *gaugeAggregator
+
 	config metricConfig
 }
 
+// severityAttributeKey is the metric attribute carrying the event severity
+// when IncludeSeverityAttribute is set.
+const severityAttributeKey = "severity"
+
+// signalAttributeKey is the metric attribute carrying the signal name when
+// IncludeSignalAttribute is set.
+const signalAttributeKey = "signal"
+
 // metricsHandler manages auto-conversion of signals to OTEL metrics.
 type metricsHandler struct {
-	meter       metric.Meter
-	instruments map[string]*metricInstrument // signal name → instrument
-	contextKeys []ContextKey
+	meter              metric.Meter
+	instruments        map[string][]*metricInstrument // signal name → instruments
+	excludeFields      map[string]struct{}            // field keys dropped before attribute conversion
+	contextKeys        []ContextKey
+	registrations      []metric.Registration // observable gauge callbacks, unregistered on Close
+	keepUnserializable bool                  // placeholder for custom fields that fail JSON serialization
+	maxAttributeBytes  int                   // truncate longer string and bytes values, 0 = no limit
 }
 
 // newMetricsHandler creates a metrics handler from config.
@@ -45,9 +73,12 @@ func newMetricsHandler(s *Aperture) (*metricsHandler, error) {
 	}
 
 	mh := &metricsHandler{
-		meter:       s.meterProvider.Meter("capitan"),
-		instruments: make(map[string]*metricInstrument),
-		contextKeys: contextKeys,
+		meter:              s.meterProvider.Meter(s.config.scopeName()),
+		instruments:        make(map[string][]*metricInstrument),
+		contextKeys:        contextKeys,
+		keepUnserializable: s.config.KeepUnserializable,
+		maxAttributeBytes:  s.config.MaxAttributeBytes,
+		excludeFields:      fieldKeySet(s.config.ExcludeFields),
 	}
 
 	// Pre-create all configured instruments
@@ -56,13 +87,27 @@ func newMetricsHandler(s *Aperture) (*metricsHandler, error) {
 		if mc.Type == "" {
 			mc.Type = MetricTypeCounter
 		}
+		if mc.DurationUnit == "" {
+			mc.DurationUnit = defaultDurationUnit(mc.Type)
+		}
+		if mc.Aggregation == "" {
+			mc.Aggregation = GaugeAggregationLast
+		}
+		if mc.FilterKeyName != "" && mc.FilterValue == "" {
+			mc.FilterValue = "true"
+		}
 
 		// Validate configuration
 		if err := validateMetricConfig(mc); err != nil {
+			mh.Close()
 			return nil, fmt.Errorf("invalid metric config for signal %q: %w", mc.SignalName, err)
 		}
 
-		inst := &metricInstrument{config: mc}
+		inst := &metricInstrument{
+			config:        mc,
+			staticAttrs:   staticMetricAttributes(mc.Attributes),
+			allowedFields: fieldKeySet(mc.AttributeAllowlist),
+		}
 
 		// Create appropriate instrument based on type
 		var err error
@@ -76,19 +121,138 @@ func newMetricsHandler(s *Aperture) (*metricsHandler, error) {
 		case MetricTypeHistogram:
 			err = mh.createHistogram(inst)
 		default:
+			mh.Close()
 			return nil, fmt.Errorf("unknown metric type: %s", mc.Type)
 		}
 
+		if err != nil && s.config.BestEffort {
+			// Skip this metric and keep the rest
+			s.internalObserver.emit(context.Background(), SignalConfigError,
+				internalSignal.Field(mc.SignalName),
+				internalMetricName.Field(mc.Name),
+				internalReason.Field(err.Error()),
+			)
+			continue
+		}
 		if err != nil {
+			mh.Close() // release callbacks registered for earlier instruments
 			return nil, fmt.Errorf("creating %s for signal %q: %w", mc.Type, mc.SignalName, err)
 		}
 
-		mh.instruments[mc.SignalName] = inst
+		// A signal may drive several instruments (e.g. a counter and a histogram)
+		mh.instruments[mc.SignalName] = append(mh.instruments[mc.SignalName], inst)
 	}
 
 	return mh, nil
 }
 
+// names returns the sorted names of all configured instruments.
+func (mh *metricsHandler) names() []string {
+	if mh == nil {
+		return nil
+	}
+
+	var names []string
+	for _, instruments := range mh.instruments {
+		for _, inst := range instruments {
+			names = append(names, inst.config.Name)
+		}
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// handles reports whether any instrument is configured for the signal.
+func (mh *metricsHandler) handles(signalName string) bool {
+	if mh == nil {
+		return false
+	}
+	_, ok := mh.instruments[signalName]
+	return ok
+}
+
+// staticMetricAttributes converts configured constant attributes to OTEL attributes.
+// Keys are sorted so the resulting slice is deterministic.
+func staticMetricAttributes(m map[string]string) []attribute.KeyValue {
+	if len(m) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	attrs := make([]attribute.KeyValue, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, attribute.String(k, m[k]))
+	}
+
+	return attrs
+}
+
+// fieldKeySet builds a lookup set from field key names.
+// Returns nil if keys is empty (all fields allowed).
+func fieldKeySet(keys []string) map[string]struct{} {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	set := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		set[k] = struct{}{}
+	}
+	return set
+}
+
+// defaultDurationUnit returns the duration unit used when none is configured.
+// Histograms record float64 milliseconds (the conventional latency unit);
+// gauges and up/down counters record exact int64 nanoseconds so deltas are lossless.
+func defaultDurationUnit(t MetricType) DurationUnit {
+	if t == MetricTypeHistogram {
+		return DurationUnitMilliseconds
+	}
+	return DurationUnitNanoseconds
+}
+
+// histogramType returns the configured histogram aggregation, defaulting to
+// HistogramTypeExplicit.
+func histogramType(mc metricConfig) HistogramType {
+	if mc.HistogramType == "" {
+		return HistogramTypeExplicit
+	}
+	return mc.HistogramType
+}
+
+// reportHistogramTypeChanges emits SignalMetricHistogramTypeChanged for each
+// histogram whose type differs from the one it had in prev. The meter
+// provider's views were built for the old type and cannot follow the change.
+func (s *Aperture) reportHistogramTypeChanges(prev []metricConfig) {
+	before := make(map[string]HistogramType)
+	for _, mc := range prev {
+		if mc.Type == MetricTypeHistogram {
+			before[mc.Name] = histogramType(mc)
+		}
+	}
+
+	for _, mc := range s.config.Metrics {
+		if mc.Type != MetricTypeHistogram {
+			continue
+		}
+		old, ok := before[mc.Name]
+		if !ok || old == histogramType(mc) {
+			continue
+		}
+		s.internalObserver.emit(context.Background(), SignalMetricHistogramTypeChanged,
+			internalSignal.Field(mc.SignalName),
+			internalMetricName.Field(mc.Name),
+			internalReason.Field(fmt.Sprintf("histogram_type changed from %q to %q", old, histogramType(mc))),
+		)
+	}
+}
+
 // validateMetricConfig checks if the metric configuration is valid.
 func validateMetricConfig(mc metricConfig) error {
 	if mc.SignalName == "" {
@@ -113,6 +277,7 @@ func (mh *metricsHandler) createCounter(inst *metricInstrument) error {
 	counter, err := mh.meter.Int64Counter(
 		inst.config.Name,
 		metric.WithDescription(inst.config.Description),
+		metric.WithUnit(inst.config.Unit),
 	)
 	if err != nil {
 		return err
@@ -127,6 +292,7 @@ func (mh *metricsHandler) createUpDownCounter(inst *metricInstrument) error {
 	int64Counter, err := mh.meter.Int64UpDownCounter(
 		inst.config.Name,
 		metric.WithDescription(inst.config.Description),
+		metric.WithUnit(inst.config.Unit),
 	)
 	if err != nil {
 		return err
@@ -136,6 +302,7 @@ func (mh *metricsHandler) createUpDownCounter(inst *metricInstrument) error {
 	float64Counter, err := mh.meter.Float64UpDownCounter(
 		inst.config.Name+"_f64",
 		metric.WithDescription(inst.config.Description),
+		metric.WithUnit(inst.config.Unit),
 	)
 	if err != nil {
 		return err
@@ -146,10 +313,19 @@ func (mh *metricsHandler) createUpDownCounter(inst *metricInstrument) error {
 }
 
 // createGauge creates gauge instruments (both int64 and float64).
+//
+// Gauges using the "last" aggregation are synchronous. Other aggregations use
+// observable gauges whose callback reports the value accumulated since the
+// previous collection (see gaugeAggregator).
 func (mh *metricsHandler) createGauge(inst *metricInstrument) error {
+	if inst.config.Aggregation != GaugeAggregationLast {
+		return mh.createAggregatedGauge(inst)
+	}
+
 	int64Gauge, err := mh.meter.Int64Gauge(
 		inst.config.Name,
 		metric.WithDescription(inst.config.Description),
+		metric.WithUnit(inst.config.Unit),
 	)
 	if err != nil {
 		return err
@@ -159,6 +335,7 @@ func (mh *metricsHandler) createGauge(inst *metricInstrument) error {
 	float64Gauge, err := mh.meter.Float64Gauge(
 		inst.config.Name+"_f64",
 		metric.WithDescription(inst.config.Description),
+		metric.WithUnit(inst.config.Unit),
 	)
 	if err != nil {
 		return err
@@ -168,11 +345,45 @@ func (mh *metricsHandler) createGauge(inst *metricInstrument) error {
 	return nil
 }
 
+// createAggregatedGauge creates observable gauge instruments (both int64 and float64)
+// backed by a gaugeAggregator.
+func (mh *metricsHandler) createAggregatedGauge(inst *metricInstrument) error {
+	int64Gauge, err := mh.meter.Int64ObservableGauge(
+		inst.config.Name,
+		metric.WithDescription(inst.config.Description),
+		metric.WithUnit(inst.config.Unit),
+	)
+	if err != nil {
+		return err
+	}
+
+	float64Gauge, err := mh.meter.Float64ObservableGauge(
+		inst.config.Name+"_f64",
+		metric.WithDescription(inst.config.Description),
+		metric.WithUnit(inst.config.Unit),
+	)
+	if err != nil {
+		return err
+	}
+
+	inst.aggregator = newGaugeAggregator(inst.config.Aggregation, int64Gauge, float64Gauge)
+
+	reg, err := mh.meter.RegisterCallback(inst.aggregator.observe, int64Gauge, float64Gauge)
+	if err != nil {
+		return err
+	}
+	mh.registrations = append(mh.registrations, reg)
+
+	return nil
+}
+
 // createHistogram creates histogram instruments (both int64 and float64).
 func (mh *metricsHandler) createHistogram(inst *metricInstrument) error {
 	int64Histogram, err := mh.meter.Int64Histogram(
 		inst.config.Name,
 		metric.WithDescription(inst.config.Description),
+		metric.WithUnit(inst.config.Unit),
+		metric.WithExplicitBucketBoundaries(inst.config.Buckets...),
 	)
 	if err != nil {
 		return err
@@ -182,6 +393,8 @@ func (mh *metricsHandler) createHistogram(inst *metricInstrument) error {
 	float64Histogram, err := mh.meter.Float64Histogram(
 		inst.config.Name+"_f64",
 		metric.WithDescription(inst.config.Description),
+		metric.WithUnit(inst.config.Unit),
+		metric.WithExplicitBucketBoundaries(inst.config.Buckets...),
 	)
 	if err != nil {
 		return err
@@ -192,54 +405,84 @@ func (mh *metricsHandler) createHistogram(inst *metricInstrument) error {
 }
 
 // handleEvent processes a capitan event and records metrics.
-func (mh *metricsHandler) handleEvent(ctx context.Context, e *capitan.Event, internal *internalObserver) {
+//
+// static holds instance-wide attributes; event fields with the same key take precedence.
+func (mh *metricsHandler) handleEvent(ctx context.Context, e *capitan.Event, static []attribute.KeyValue, internal *internalObserver) {
 	if mh == nil {
 		return
 	}
 
 	// Match signal by name
-	inst, ok := mh.instruments[e.Signal().Name()]
+	instruments, ok := mh.instruments[e.Signal().Name()]
 	if !ok {
 		return
 	}
 
-	// Convert fields to metric attributes
-	attrs := fieldsToMetricAttributes(e.Fields())
-
-	// Extract and add context values if configured
+	// Extract context values if configured
+	var contextAttrs []attribute.KeyValue
 	if len(mh.contextKeys) > 0 {
-		contextAttrs := extractContextValuesForMetrics(ctx, mh.contextKeys)
-		attrs = append(attrs, contextAttrs...)
+		contextAttrs = extractContextValuesForMetrics(ctx, mh.contextKeys)
 	}
 
-	opts := metric.WithAttributes(attrs...)
+	// Static attributes come first so event fields with the same key win
+	fields := withoutFields(e.Fields(), mh.excludeFields)
+	attrs := slices.Concat(static, fieldsToMetricAttributes(fields, nil, nil, mh.keepUnserializable, mh.maxAttributeBytes), contextAttrs)
+	eventAttrSet := attribute.NewSet(attrs...)
+
+	// Record every instrument configured for this signal
+	for _, inst := range instruments {
+		if !matchesFilter(ctx, inst, e, internal) {
+			continue
+		}
+
+		// Share the event attribute set unless this instrument customizes it
+		attrSet := eventAttrSet
+		instAttrs := attrs
+		customized := false
+		if inst.allowedFields != nil || len(inst.config.AttributeRename) > 0 {
+			fieldAttrs := fieldsToMetricAttributes(fields, inst.allowedFields, inst.config.AttributeRename, mh.keepUnserializable, mh.maxAttributeBytes)
+			instAttrs = slices.Concat(static, fieldAttrs, contextAttrs)
+			customized = true
+		}
+		if inst.config.IncludeSeverityAttribute {
+			instAttrs = append(slices.Clip(instAttrs), attribute.String(severityAttributeKey, string(e.Severity())))
+			customized = true
+		}
+		if inst.config.IncludeSignalAttribute {
+			instAttrs = append(slices.Clip(instAttrs), attribute.String(signalAttributeKey, e.Signal().Name()))
+			customized = true
+		}
+		if len(inst.staticAttrs) > 0 {
+			// Static attributes come last so they win over event fields with the same key
+			instAttrs = append(slices.Clip(instAttrs), inst.staticAttrs...)
+			customized = true
+		}
+		if customized {
+			attrSet = attribute.NewSet(instAttrs...)
+		}
+		opts := metric.WithAttributeSet(attrSet)
 
-	// Handle based on metric type
-	switch inst.config.Type {
-	case MetricTypeCounter:
-		// Counter just counts signal occurrences
-		inst.int64Counter.Add(ctx, 1, opts)
+		switch inst.config.Type {
+		case MetricTypeCounter:
+			// Counter just counts signal occurrences
+			inst.int64Counter.Add(ctx, 1, opts)
 
-	case MetricTypeUpDownCounter:
-		mh.recordUpDownCounter(ctx, inst, e, opts, internal)
+		case MetricTypeUpDownCounter:
+			mh.recordUpDownCounter(ctx, inst, e, opts, internal)
 
-	case MetricTypeGauge:
-		mh.recordGauge(ctx, inst, e, opts, internal)
+		case MetricTypeGauge:
+			mh.recordGauge(ctx, inst, e, attrSet, internal)
 
-	case MetricTypeHistogram:
-		mh.recordHistogram(ctx, inst, e, opts, internal)
+		case MetricTypeHistogram:
+			mh.recordHistogram(ctx, inst, e, opts, internal)
+		}
 	}
 }
 
 // recordUpDownCounter extracts value from event and records it.
 func (*metricsHandler) recordUpDownCounter(ctx context.Context, inst *metricInstrument, e *capitan.Event, opts metric.AddOption, internal *internalObserver) {
-	value := extractNumericValueByName(e, inst.config.ValueKeyName)
+	value := extractMetricValue(ctx, inst, e, internal)
 	if value == nil {
-		internal.emit(ctx, SignalMetricValueMissing,
-			internalSignal.Field(e.Signal().Name()),
-			internalMetricName.Field(inst.config.Name),
-			internalValueKey.Field(inst.config.ValueKeyName),
-		)
 		return
 	}
 
@@ -251,17 +494,28 @@ func (*metricsHandler) recordUpDownCounter(ctx context.Context, inst *metricInst
 }
 
 // recordGauge extracts value from event and records it.
-func (*metricsHandler) recordGauge(ctx context.Context, inst *metricInstrument, e *capitan.Event, opts metric.RecordOption, internal *internalObserver) {
-	value := extractNumericValueByName(e, inst.config.ValueKeyName)
+// Aggregated gauges accumulate the value until the next collection instead.
+func (*metricsHandler) recordGauge(ctx context.Context, inst *metricInstrument, e *capitan.Event, attrs attribute.Set, internal *internalObserver) {
+	value := extractMetricValue(ctx, inst, e, internal)
 	if value == nil {
-		internal.emit(ctx, SignalMetricValueMissing,
-			internalSignal.Field(e.Signal().Name()),
-			internalMetricName.Field(inst.config.Name),
-			internalValueKey.Field(inst.config.ValueKeyName),
-		)
 		return
 	}
 
+	if inst.aggregator != nil {
+		// The value field is itself an attribute; drop it so values with
+		// otherwise identical attributes aggregate together.
+		valueKey := attribute.Key(inst.config.ValueKeyName)
+		if to, ok := inst.config.AttributeRename[inst.config.ValueKeyName]; ok {
+			valueKey = attribute.Key(to)
+		}
+		aggAttrs, _ := attrs.Filter(func(kv attribute.KeyValue) bool {
+			return kv.Key != valueKey
+		})
+		inst.aggregator.record(value, aggAttrs)
+		return
+	}
+
+	opts := metric.WithAttributeSet(attrs)
 	if value.isFloat {
 		inst.float64Gauge.Record(ctx, value.asFloat64(), opts)
 	} else {
@@ -271,13 +525,8 @@ func (*metricsHandler) recordGauge(ctx context.Context, inst *metricInstrument,
 
 // recordHistogram extracts value from event and records it.
 func (*metricsHandler) recordHistogram(ctx context.Context, inst *metricInstrument, e *capitan.Event, opts metric.RecordOption, internal *internalObserver) {
-	value := extractNumericValueByName(e, inst.config.ValueKeyName)
+	value := extractMetricValue(ctx, inst, e, internal)
 	if value == nil {
-		internal.emit(ctx, SignalMetricValueMissing,
-			internalSignal.Field(e.Signal().Name()),
-			internalMetricName.Field(inst.config.Name),
-			internalValueKey.Field(inst.config.ValueKeyName),
-		)
 		return
 	}
 
@@ -288,6 +537,93 @@ func (*metricsHandler) recordHistogram(ctx context.Context, inst *metricInstrume
 	}
 }
 
+// extractMetricValue extracts the configured value for inst from the event.
+// Returns nil and emits a diagnostic if no usable value is present.
+func extractMetricValue(ctx context.Context, inst *metricInstrument, e *capitan.Event, internal *internalObserver) *numericValue {
+	value := extractNumericValueByName(e, inst.config.ValueKeyName, inst.config.DurationUnit)
+	if value != nil {
+		return value
+	}
+
+	// Opt-in: legacy emitters may carry numbers in string fields
+	if inst.config.ParseStringValue {
+		if raw, ok := findStringFieldByName(e, inst.config.ValueKeyName); ok {
+			if value := parseNumericString(raw); value != nil {
+				return value
+			}
+			internal.emit(ctx, SignalMetricValueInvalid,
+				internalSignal.Field(e.Signal().Name()),
+				internalMetricName.Field(inst.config.Name),
+				internalValueKey.Field(inst.config.ValueKeyName),
+				internalRawValue.Field(raw),
+			)
+			return nil
+		}
+	}
+
+	internal.emit(ctx, SignalMetricValueMissing,
+		internalSignal.Field(e.Signal().Name()),
+		internalMetricName.Field(inst.config.Name),
+		internalValueKey.Field(inst.config.ValueKeyName),
+	)
+	return nil
+}
+
+// matchesFilter reports whether the event passes inst's filter field check.
+// Emits a diagnostic if the filter field is missing.
+func matchesFilter(ctx context.Context, inst *metricInstrument, e *capitan.Event, internal *internalObserver) bool {
+	keyName := inst.config.FilterKeyName
+	if keyName == "" {
+		return true
+	}
+	for _, f := range e.Fields() {
+		if f.Key().Name() == keyName {
+			return fmt.Sprint(f.Value()) == inst.config.FilterValue
+		}
+	}
+	internal.emit(ctx, SignalMetricFilterMissing,
+		internalSignal.Field(e.Signal().Name()),
+		internalMetricName.Field(inst.config.Name),
+		internalFilterKey.Field(keyName),
+	)
+	return false
+}
+
+// findStringFieldByName returns the value of a string field by key name.
+func findStringFieldByName(e *capitan.Event, keyName string) (string, bool) {
+	for _, f := range e.Fields() {
+		if f.Key().Name() != keyName || f.Variant() != capitan.VariantString {
+			continue
+		}
+		if gf, ok := f.(capitan.GenericField[string]); ok {
+			return gf.Get(), true
+		}
+	}
+	return "", false
+}
+
+// parseNumericString parses a decimal string as a float64 metric value.
+// Returns nil if the string is not a finite number.
+func parseNumericString(raw string) *numericValue {
+	f, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil
+	}
+	return &numericValue{floatValue: f, isFloat: true}
+}
+
+// Close unregisters observable instrument callbacks.
+func (mh *metricsHandler) Close() {
+	if mh == nil {
+		return
+	}
+
+	for _, reg := range mh.registrations {
+		_ = reg.Unregister() //nolint:errcheck // best-effort cleanup
+	}
+	mh.registrations = nil
+}
+
 // numericValue holds a numeric value that can be converted to int64 or float64.
 type numericValue struct {
 	intValue   int64
@@ -310,7 +646,9 @@ func (n *numericValue) asFloat64() float64 {
 }
 
 // extractNumericValueByName extracts a numeric value from event fields by key name.
-func extractNumericValueByName(e *capitan.Event, keyName string) *numericValue {
+// Duration fields are converted according to unit: exact int64 nanoseconds for
+// DurationUnitNanoseconds, float64 milliseconds otherwise.
+func extractNumericValueByName(e *capitan.Event, keyName string, unit DurationUnit) *numericValue {
 	if keyName == "" {
 		return nil
 	}
@@ -337,7 +675,7 @@ func extractNumericValueByName(e *capitan.Event, keyName string) *numericValue {
 			}
 		case capitan.VariantUint:
 			if gf, ok := f.(capitan.GenericField[uint]); ok {
-				return &numericValue{intValue: safeUintToInt64(gf.Get())}
+				return uint64ToNumeric(uint64(gf.Get()))
 			}
 		case capitan.VariantUint32:
 			if gf, ok := f.(capitan.GenericField[uint32]); ok {
@@ -345,7 +683,7 @@ func extractNumericValueByName(e *capitan.Event, keyName string) *numericValue {
 			}
 		case capitan.VariantUint64:
 			if gf, ok := f.(capitan.GenericField[uint64]); ok {
-				return &numericValue{intValue: safeUint64ToInt64(gf.Get())}
+				return uint64ToNumeric(gf.Get())
 			}
 		case capitan.VariantFloat32:
 			if gf, ok := f.(capitan.GenericField[float32]); ok {
@@ -357,11 +695,113 @@ func extractNumericValueByName(e *capitan.Event, keyName string) *numericValue {
 			}
 		case capitan.VariantDuration:
 			if gf, ok := f.(capitan.GenericField[time.Duration]); ok {
+				if unit == DurationUnitNanoseconds {
+					return &numericValue{intValue: int64(gf.Get())}
+				}
 				// Convert duration to milliseconds
 				return &numericValue{floatValue: float64(gf.Get()) / float64(time.Millisecond), isFloat: true}
 			}
+		case capitan.VariantBool:
+			if gf, ok := f.(capitan.GenericField[bool]); ok {
+				// Booleans map to 1 (true) and 0 (false)
+				if gf.Get() {
+					return &numericValue{intValue: 1}
+				}
+				return &numericValue{intValue: 0}
+			}
+		case capitan.VariantString, capitan.VariantTime, capitan.VariantBytes, capitan.VariantError:
+			// Built-in non-numeric variants never carry a metric value
+		default:
+			// Custom types: coerce numeric underlying values (e.g. type Money int64)
+			if v := fieldToNumeric(f); v != nil {
+				return v
+			}
 		}
 	}
 
 	return nil
 }
+
+// gaugeAggregator accumulates gauge values between collections for the
+// "max", "min", and "sum" aggregations.
+//
+// Values are tracked per attribute set, separately for int64 and float64
+// measurements. On each collection the observable gauge callback reports the
+// accumulated values and then clears them, so every reporting interval starts
+// fresh. An interval with no recorded values produces no data point.
+type gaugeAggregator struct {
+	int64Gauge   metric.Int64ObservableGauge
+	float64Gauge metric.Float64ObservableGauge
+	int64s       map[attribute.Distinct]*gaugeAggregate[int64]
+	float64s     map[attribute.Distinct]*gaugeAggregate[float64]
+	mode         GaugeAggregation
+	mu           sync.Mutex
+}
+
+// gaugeAggregate is the running value for a single attribute set.
+type gaugeAggregate[N int64 | float64] struct {
+	attrs attribute.Set
+	value N
+}
+
+// newGaugeAggregator creates an aggregator reporting through the given observable gauges.
+func newGaugeAggregator(mode GaugeAggregation, int64Gauge metric.Int64ObservableGauge, float64Gauge metric.Float64ObservableGauge) *gaugeAggregator {
+	return &gaugeAggregator{
+		int64Gauge:   int64Gauge,
+		float64Gauge: float64Gauge,
+		int64s:       make(map[attribute.Distinct]*gaugeAggregate[int64]),
+		float64s:     make(map[attribute.Distinct]*gaugeAggregate[float64]),
+		mode:         mode,
+	}
+}
+
+// record folds a value into the running aggregate for its attribute set.
+func (ga *gaugeAggregator) record(value *numericValue, attrs attribute.Set) {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+
+	if value.isFloat {
+		accumulate(ga.float64s, ga.mode, value.asFloat64(), attrs)
+	} else {
+		accumulate(ga.int64s, ga.mode, value.asInt64(), attrs)
+	}
+}
+
+// observe reports accumulated values and resets state for the next interval.
+func (ga *gaugeAggregator) observe(_ context.Context, o metric.Observer) error {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+
+	for key, agg := range ga.int64s {
+		o.ObserveInt64(ga.int64Gauge, agg.value, metric.WithAttributeSet(agg.attrs))
+		delete(ga.int64s, key)
+	}
+	for key, agg := range ga.float64s {
+		o.ObserveFloat64(ga.float64Gauge, agg.value, metric.WithAttributeSet(agg.attrs))
+		delete(ga.float64s, key)
+	}
+
+	return nil
+}
+
+// accumulate combines value into the aggregate for attrs according to mode.
+func accumulate[N int64 | float64](aggs map[attribute.Distinct]*gaugeAggregate[N], mode GaugeAggregation, value N, attrs attribute.Set) {
+	key := attrs.Equivalent()
+
+	agg, ok := aggs[key]
+	if !ok {
+		aggs[key] = &gaugeAggregate[N]{attrs: attrs, value: value}
+		return
+	}
+
+	switch mode {
+	case GaugeAggregationMax:
+		agg.value = max(agg.value, value)
+	case GaugeAggregationMin:
+		agg.value = min(agg.value, value)
+	case GaugeAggregationSum:
+		agg.value += value
+	default:
+		agg.value = value
+	}
+}
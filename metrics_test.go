@@ -2,22 +2,28 @@ package aperture
 
 import (
 	"context"
+	"math"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	apertesting "github.com/zoobzio/aperture/testing"
 	"github.com/zoobzio/capitan"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestMetricTypeCounter(t *testing.T) {
 	ctx := context.Background()
 	cap := capitan.New()
 
-	pvs, err := apertesting.TestProviders(ctx, "test-service", "v1.0.0", "localhost:4318")
-	if err != nil {
-		t.Fatalf("failed to create providers: %v", err)
-	}
+	reader := apertesting.NewManualMetricReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader.Reader()))
+	defer meterProvider.Shutdown(ctx)
 
 	orderCreated := capitan.NewSignal("order.created", "Order Created")
 
@@ -32,7 +38,7 @@ func TestMetricTypeCounter(t *testing.T) {
 		},
 	}
 
-	sh, err := New(cap, pvs.Log, pvs.Meter, pvs.Trace)
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
 	if err != nil {
 		t.Fatalf("failed to create Aperture: %v", err)
 	}
@@ -48,8 +54,18 @@ func TestMetricTypeCounter(t *testing.T) {
 	cap.Emit(ctx, orderCreated)
 	cap.Emit(ctx, orderCreated)
 
-	time.Sleep(100 * time.Millisecond)
-	// Counter should be 3 (validation would require OTLP capture)
+	sh.Flush(ctx)
+
+	m, ok := reader.Metric(ctx, "orders_created_total")
+	if !ok {
+		t.Fatal("orders_created_total not recorded")
+	}
+	if m.Type != "counter" {
+		t.Errorf("expected counter, got %s", m.Type)
+	}
+	if m.Total() != 3 {
+		t.Errorf("expected counter to be 3, got %v", m.Total())
+	}
 }
 
 func TestMetricTypeGaugeInt64(t *testing.T) {
@@ -140,10 +156,9 @@ func TestMetricTypeHistogramDuration(t *testing.T) {
 	ctx := context.Background()
 	cap := capitan.New()
 
-	pvs, err := apertesting.TestProviders(ctx, "test-service", "v1.0.0", "localhost:4318")
-	if err != nil {
-		t.Fatalf("failed to create providers: %v", err)
-	}
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
 
 	requestCompleted := capitan.NewSignal("request.completed", "Request Completed")
 	durationKey := capitan.NewDurationKey("duration")
@@ -156,11 +171,12 @@ func TestMetricTypeHistogramDuration(t *testing.T) {
 				Type:        "histogram",
 				ValueKey:    "duration",
 				Description: "Request duration in milliseconds",
+				Unit:        "ms",
 			},
 		},
 	}
 
-	sh, err := New(cap, pvs.Log, pvs.Meter, pvs.Trace)
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
 	if err != nil {
 		t.Fatalf("failed to create Aperture: %v", err)
 	}
@@ -177,7 +193,39 @@ func TestMetricTypeHistogramDuration(t *testing.T) {
 	cap.Emit(ctx, requestCompleted, durationKey.Field(180*time.Millisecond))
 
 	time.Sleep(100 * time.Millisecond)
-	// Histogram should contain distribution of durations
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	// Durations are recorded as float64 milliseconds
+	var found bool
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			data, ok := m.Data.(metricdata.Histogram[float64])
+			if !ok || !strings.HasPrefix(m.Name, "request_duration_ms") {
+				continue
+			}
+			found = true
+			if m.Description != "Request duration in milliseconds" {
+				t.Errorf("description = %q, want %q", m.Description, "Request duration in milliseconds")
+			}
+			if m.Unit != "ms" {
+				t.Errorf("unit = %q, want ms", m.Unit)
+			}
+			var count uint64
+			for _, dp := range data.DataPoints {
+				count += dp.Count
+			}
+			if count != 3 {
+				t.Errorf("histogram count = %d, want 3", count)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("request_duration_ms histogram not exported")
+	}
 }
 
 func TestMetricTypeHistogramInt64(t *testing.T) {
@@ -660,7 +708,7 @@ func TestMetricsHandler_NilHandler(t *testing.T) {
 	var mh *metricsHandler
 
 	// Should not panic
-	mh.handleEvent(ctx, nil, nil)
+	mh.handleEvent(ctx, nil, nil, nil)
 }
 
 func TestValidateMetricConfig_EmptyName(t *testing.T) {
@@ -737,7 +785,7 @@ func TestExtractNumericValue_NilKeyName(t *testing.T) {
 	}
 
 	// Extract with empty key name
-	result := extractNumericValueByName(evt, "")
+	result := extractNumericValueByName(evt, "", DurationUnitMilliseconds)
 	if result != nil {
 		t.Error("expected nil for empty key name")
 	}
@@ -777,3 +825,1296 @@ func TestInvalidMetricType(t *testing.T) {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
+
+func TestMultipleMetricsPerSignal(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	requestCompleted := capitan.NewSignal("request.completed", "Request Completed")
+	sizeKey := capitan.NewInt64Key("size_bytes")
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{
+				Signal: "request.completed",
+				Name:   "requests_total",
+				Type:   "counter",
+			},
+			{
+				Signal:   "request.completed",
+				Name:     "response_size_bytes",
+				Type:     "histogram",
+				ValueKey: "size_bytes",
+			},
+		},
+	}
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(schema)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	// One signal should feed both instruments
+	cap.Emit(ctx, requestCompleted, sizeKey.Field(512))
+	cap.Emit(ctx, requestCompleted, sizeKey.Field(1024))
+
+	time.Sleep(100 * time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	var counterTotal int64
+	var histogramCount uint64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				if m.Name == "requests_total" {
+					for _, dp := range data.DataPoints {
+						counterTotal += dp.Value
+					}
+				}
+			case metricdata.Histogram[int64]:
+				if m.Name == "response_size_bytes" {
+					for _, dp := range data.DataPoints {
+						histogramCount += dp.Count
+					}
+				}
+			}
+		}
+	}
+
+	if counterTotal != 2 {
+		t.Errorf("requests_total = %d, want 2", counterTotal)
+	}
+	if histogramCount != 2 {
+		t.Errorf("response_size_bytes count = %d, want 2", histogramCount)
+	}
+}
+
+func TestDurationUnit_PerMetricType(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	jobDone := capitan.NewSignal("job.done", "Job Done")
+	elapsedKey := capitan.NewDurationKey("elapsed")
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			// Gauge defaults to exact nanoseconds
+			{Signal: "job.done", Name: "job_elapsed", Type: "gauge", ValueKey: "elapsed"},
+			// Histogram defaults to float milliseconds
+			{Signal: "job.done", Name: "job_latency", Type: "histogram", ValueKey: "elapsed"},
+			// Explicit unit overrides the default
+			{Signal: "job.done", Name: "job_backlog", Type: "updowncounter", ValueKey: "elapsed", DurationUnit: "ms"},
+		},
+	}
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(schema)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	elapsed := 1500*time.Millisecond + 123*time.Nanosecond
+	cap.Emit(ctx, jobDone, elapsedKey.Field(elapsed))
+
+	time.Sleep(100 * time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	wantMs := float64(elapsed) / float64(time.Millisecond)
+	var sawGauge, sawHistogram, sawUpDown bool
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Gauge[int64]:
+				if m.Name == "job_elapsed" && len(data.DataPoints) == 1 {
+					sawGauge = true
+					if got := data.DataPoints[0].Value; got != int64(elapsed) {
+						t.Errorf("job_elapsed = %d, want %d", got, int64(elapsed))
+					}
+				}
+			case metricdata.Histogram[float64]:
+				if m.Name == "job_latency_f64" && len(data.DataPoints) == 1 {
+					sawHistogram = true
+					if got := data.DataPoints[0].Sum; got != wantMs {
+						t.Errorf("job_latency_f64 sum = %v, want %v", got, wantMs)
+					}
+				}
+			case metricdata.Sum[float64]:
+				if m.Name == "job_backlog_f64" && len(data.DataPoints) == 1 {
+					sawUpDown = true
+					if got := data.DataPoints[0].Value; got != wantMs {
+						t.Errorf("job_backlog_f64 = %v, want %v", got, wantMs)
+					}
+				}
+			}
+		}
+	}
+
+	if !sawGauge {
+		t.Error("expected int64 gauge job_elapsed to be recorded in nanoseconds")
+	}
+	if !sawHistogram {
+		t.Error("expected float64 histogram job_latency_f64 to be recorded in milliseconds")
+	}
+	if !sawUpDown {
+		t.Error("expected float64 updowncounter job_backlog_f64 to be recorded in milliseconds")
+	}
+}
+
+func TestDefaultDurationUnit(t *testing.T) {
+	tests := []struct {
+		metricType MetricType
+		want       DurationUnit
+	}{
+		{MetricTypeCounter, DurationUnitNanoseconds},
+		{MetricTypeUpDownCounter, DurationUnitNanoseconds},
+		{MetricTypeGauge, DurationUnitNanoseconds},
+		{MetricTypeHistogram, DurationUnitMilliseconds},
+	}
+
+	for _, tt := range tests {
+		if got := defaultDurationUnit(tt.metricType); got != tt.want {
+			t.Errorf("defaultDurationUnit(%s) = %q, want %q", tt.metricType, got, tt.want)
+		}
+	}
+}
+
+func TestGaugeAggregation(t *testing.T) {
+	tests := []struct {
+		aggregation string
+		want        int64
+	}{
+		{"max", 9},
+		{"min", 3},
+		{"sum", 17},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.aggregation, func(t *testing.T) {
+			ctx := context.Background()
+			cap := capitan.New()
+
+			reader := sdkmetric.NewManualReader()
+			meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+			defer meterProvider.Shutdown(ctx)
+
+			queueDepth := capitan.NewSignal("queue.depth", "Queue Depth")
+			depthKey := capitan.NewInt64Key("depth")
+
+			schema := Schema{
+				Metrics: []MetricSchema{
+					{
+						Signal:      "queue.depth",
+						Name:        "queue_depth",
+						Type:        "gauge",
+						ValueKey:    "depth",
+						Aggregation: tt.aggregation,
+					},
+				},
+			}
+
+			sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+			if err != nil {
+				t.Fatalf("failed to create Aperture: %v", err)
+			}
+			defer sh.Close()
+
+			err = sh.Apply(schema)
+			if err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+
+			cap.Emit(ctx, queueDepth, depthKey.Field(5))
+			cap.Emit(ctx, queueDepth, depthKey.Field(9))
+			cap.Emit(ctx, queueDepth, depthKey.Field(3))
+			time.Sleep(100 * time.Millisecond)
+
+			got, ok := collectInt64Gauge(t, reader, "queue_depth")
+			if !ok {
+				t.Fatal("expected queue_depth data point")
+			}
+			if got != tt.want {
+				t.Errorf("queue_depth = %d, want %d", got, tt.want)
+			}
+
+			// State resets at the collection boundary
+			if _, ok := collectInt64Gauge(t, reader, "queue_depth"); ok {
+				t.Error("expected no data point for an interval without values")
+			}
+
+			cap.Emit(ctx, queueDepth, depthKey.Field(4))
+			time.Sleep(100 * time.Millisecond)
+
+			got, ok = collectInt64Gauge(t, reader, "queue_depth")
+			if !ok {
+				t.Fatal("expected queue_depth data point after reset")
+			}
+			if got != 4 {
+				t.Errorf("queue_depth after reset = %d, want 4", got)
+			}
+		})
+	}
+}
+
+// collectInt64Gauge collects from reader and returns the single data point of the named int64 gauge.
+func collectInt64Gauge(t *testing.T, reader sdkmetric.Reader, name string) (int64, bool) {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			if data, ok := m.Data.(metricdata.Gauge[int64]); ok && len(data.DataPoints) == 1 {
+				return data.DataPoints[0].Value, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+func TestMetricStaticAttributes(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	orderCreated := capitan.NewSignal("order.created", "Order Created")
+	totalKey := capitan.NewFloat64Key("total")
+	tierKey := capitan.NewStringKey("tier")
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{
+				Signal:     "order.created",
+				Name:       "orders_total",
+				Type:       "counter",
+				Attributes: map[string]string{"tier": "premium", "region": "eu"},
+			},
+			{
+				Signal:     "order.created",
+				Name:       "order_value",
+				Type:       "histogram",
+				ValueKey:   "total",
+				Attributes: map[string]string{"tier": "premium"},
+			},
+			{
+				Signal:   "order.created",
+				Name:     "order_value_plain",
+				Type:     "histogram",
+				ValueKey: "total",
+			},
+		},
+	}
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(schema)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	// Event field with the same key is overridden by the static attribute
+	cap.Emit(ctx, orderCreated, totalKey.Field(99.5), tierKey.Field("basic"))
+	time.Sleep(100 * time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	attrsByMetric := make(map[string]attribute.Set)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					attrsByMetric[m.Name] = dp.Attributes
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					attrsByMetric[m.Name] = dp.Attributes
+				}
+			}
+		}
+	}
+
+	tests := []struct {
+		metric string
+		key    attribute.Key
+		want   string
+	}{
+		{"orders_total", "tier", "premium"},
+		{"orders_total", "region", "eu"},
+		{"order_value_f64", "tier", "premium"},
+		{"order_value_plain_f64", "tier", "basic"},
+	}
+
+	for _, tt := range tests {
+		attrs, ok := attrsByMetric[tt.metric]
+		if !ok {
+			t.Errorf("%s: no data point recorded", tt.metric)
+			continue
+		}
+		got, ok := attrs.Value(tt.key)
+		if !ok {
+			t.Errorf("%s: missing attribute %q", tt.metric, tt.key)
+			continue
+		}
+		if got.AsString() != tt.want {
+			t.Errorf("%s: %s = %q, want %q", tt.metric, tt.key, got.AsString(), tt.want)
+		}
+	}
+
+	if attrs, ok := attrsByMetric["order_value_plain_f64"]; ok && attrs.HasValue("region") {
+		t.Error("order_value_plain_f64: static attributes leaked from another metric")
+	}
+}
+
+func TestStaticMetricAttributes(t *testing.T) {
+	if attrs := staticMetricAttributes(nil); attrs != nil {
+		t.Errorf("expected nil for no attributes, got %v", attrs)
+	}
+
+	attrs := staticMetricAttributes(map[string]string{"tier": "premium", "env": "prod"})
+	if len(attrs) != 2 {
+		t.Fatalf("expected 2 attributes, got %d", len(attrs))
+	}
+	// Sorted by key
+	if attrs[0].Key != "env" || attrs[1].Key != "tier" {
+		t.Errorf("expected keys [env tier], got [%s %s]", attrs[0].Key, attrs[1].Key)
+	}
+}
+
+func TestMetricValue_BoolField(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	featureToggled := capitan.NewSignal("feature.enabled", "Feature Enabled")
+	enabledKey := capitan.NewBoolKey("enabled")
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{Signal: "feature.enabled", Name: "feature_enabled", Type: "gauge", ValueKey: "enabled"},
+			{Signal: "feature.enabled", Name: "feature_enabled_hist", Type: "histogram", ValueKey: "enabled"},
+			{Signal: "feature.enabled", Name: "feature_enabled_net", Type: "updowncounter", ValueKey: "enabled"},
+		},
+	}
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(schema)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	cap.Emit(ctx, featureToggled, enabledKey.Field(true))
+	time.Sleep(100 * time.Millisecond)
+
+	if got, ok := collectInt64Gauge(t, reader, "feature_enabled"); !ok || got != 1 {
+		t.Errorf("feature_enabled = %d (found %v), want 1", got, ok)
+	}
+
+	cap.Emit(ctx, featureToggled, enabledKey.Field(false))
+	time.Sleep(100 * time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	var sawGaugeFalse, sawHistogram, sawUpDown bool
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Gauge[int64]:
+				if m.Name != "feature_enabled" {
+					continue
+				}
+				for _, dp := range data.DataPoints {
+					if v, _ := dp.Attributes.Value("enabled"); !v.AsBool() {
+						sawGaugeFalse = true
+						if dp.Value != 0 {
+							t.Errorf("feature_enabled{enabled=false} = %d, want 0", dp.Value)
+						}
+					}
+				}
+			case metricdata.Histogram[int64]:
+				if m.Name == "feature_enabled_hist" {
+					sawHistogram = true
+					var count uint64
+					var sum int64
+					for _, dp := range data.DataPoints {
+						count += dp.Count
+						sum += dp.Sum
+					}
+					if count != 2 || sum != 1 {
+						t.Errorf("feature_enabled_hist count=%d sum=%d, want count=2 sum=1", count, sum)
+					}
+				}
+			case metricdata.Sum[int64]:
+				if m.Name == "feature_enabled_net" {
+					sawUpDown = true
+					var total int64
+					for _, dp := range data.DataPoints {
+						total += dp.Value
+					}
+					if total != 1 {
+						t.Errorf("feature_enabled_net = %d, want 1", total)
+					}
+				}
+			}
+		}
+	}
+
+	if !sawGaugeFalse {
+		t.Error("expected feature_enabled gauge to record 0 for false")
+	}
+	if !sawHistogram {
+		t.Error("expected int64 histogram feature_enabled_hist to be recorded")
+	}
+	if !sawUpDown {
+		t.Error("expected int64 updowncounter feature_enabled_net to be recorded")
+	}
+}
+
+func TestMetricValue_ParseStringValue(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	paymentReceived := capitan.NewSignal("payment.received", "Payment Received")
+	amountKey := capitan.NewStringKey("amount")
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{Signal: "payment.received", Name: "payment_amount", Type: "gauge", ValueKey: "amount", ParseStringValue: true},
+		},
+	}
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(schema)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	cap.Emit(ctx, paymentReceived, amountKey.Field("42.5"))
+	time.Sleep(100 * time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	var found bool
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if data, ok := m.Data.(metricdata.Gauge[float64]); ok && m.Name == "payment_amount_f64" && len(data.DataPoints) == 1 {
+				found = true
+				if got := data.DataPoints[0].Value; got != 42.5 {
+					t.Errorf("payment_amount_f64 = %v, want 42.5", got)
+				}
+			}
+		}
+	}
+	if !found {
+		t.Error("expected float64 gauge payment_amount_f64 to be recorded from string field")
+	}
+}
+
+func TestParseNumericString(t *testing.T) {
+	tests := []struct {
+		input string
+		want  float64
+		ok    bool
+	}{
+		{"42.5", 42.5, true},
+		{"-3", -3, true},
+		{" 7 ", 7, true},
+		{"1e3", 1000, true},
+		{"", 0, false},
+		{"abc", 0, false},
+		{"NaN", 0, false},
+		{"Inf", 0, false},
+	}
+
+	for _, tt := range tests {
+		got := parseNumericString(tt.input)
+		if (got != nil) != tt.ok {
+			t.Errorf("parseNumericString(%q) ok = %v, want %v", tt.input, got != nil, tt.ok)
+			continue
+		}
+		if got != nil && got.asFloat64() != tt.want {
+			t.Errorf("parseNumericString(%q) = %v, want %v", tt.input, got.asFloat64(), tt.want)
+		}
+	}
+}
+
+// money is a custom numeric type (amount in cents) used to test custom field extraction.
+type money int64
+
+func TestMetricValue_CustomNumericType(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	paymentReceived := capitan.NewSignal("payment.received", "Payment Received")
+	amountKey := capitan.NewKey[money]("amount", "test.Money")
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{Signal: "payment.received", Name: "payment_cents", Type: "gauge", ValueKey: "amount"},
+		},
+	}
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(schema)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	cap.Emit(ctx, paymentReceived, amountKey.Field(money(4250)))
+	time.Sleep(100 * time.Millisecond)
+
+	if got, ok := collectInt64Gauge(t, reader, "payment_cents"); !ok || got != 4250 {
+		t.Errorf("payment_cents = %d (found %v), want 4250", got, ok)
+	}
+}
+
+func TestFieldToNumeric(t *testing.T) {
+	type ratio float32
+	type count uint16
+	type label string
+
+	tests := []struct {
+		name    string
+		field   capitan.Field
+		want    float64
+		isFloat bool
+		ok      bool
+	}{
+		{"signed", capitan.NewKey[money]("v", "test.Money").Field(money(-5)), -5, false, true},
+		{"unsigned", capitan.NewKey[count]("v", "test.Count").Field(count(7)), 7, false, true},
+		{"float", capitan.NewKey[ratio]("v", "test.Ratio").Field(ratio(0.5)), 0.5, true, true},
+		{"string kind", capitan.NewKey[label]("v", "test.Label").Field(label("x")), 0, false, false},
+		{"struct", capitan.NewKey[struct{ N int }]("v", "test.Struct").Field(struct{ N int }{1}), 0, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fieldToNumeric(tt.field)
+			if (got != nil) != tt.ok {
+				t.Fatalf("fieldToNumeric() ok = %v, want %v", got != nil, tt.ok)
+			}
+			if got == nil {
+				return
+			}
+			if got.isFloat != tt.isFloat {
+				t.Errorf("isFloat = %v, want %v", got.isFloat, tt.isFloat)
+			}
+			if got.asFloat64() != tt.want {
+				t.Errorf("value = %v, want %v", got.asFloat64(), tt.want)
+			}
+		})
+	}
+}
+
+func TestMetricAttributeRename(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	orderCreated := capitan.NewSignal("order.created", "Order Created")
+	statusKey := capitan.NewStringKey("order_status")
+	regionKey := capitan.NewStringKey("region")
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{
+				Signal:          "order.created",
+				Name:            "orders_total",
+				Type:            "counter",
+				AttributeRename: map[string]string{"order_status": "status"},
+			},
+			{
+				Signal: "order.created",
+				Name:   "orders_plain_total",
+				Type:   "counter",
+			},
+		},
+	}
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	if err := sh.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	cap.Emit(ctx, orderCreated, statusKey.Field("paid"), regionKey.Field("eu"))
+	time.Sleep(100 * time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	attrsByMetric := make(map[string]attribute.Set)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if data, ok := m.Data.(metricdata.Sum[int64]); ok {
+				for _, dp := range data.DataPoints {
+					attrsByMetric[m.Name] = dp.Attributes
+				}
+			}
+		}
+	}
+
+	renamed := attrsByMetric["orders_total"]
+	if v, ok := renamed.Value("status"); !ok || v.AsString() != "paid" {
+		t.Errorf("orders_total: status = %v, want paid", v.AsString())
+	}
+	if renamed.HasValue("order_status") {
+		t.Error("orders_total: source key order_status should be renamed")
+	}
+	if v, ok := renamed.Value("region"); !ok || v.AsString() != "eu" {
+		t.Errorf("orders_total: unmatched key region should pass through, got %v", v.AsString())
+	}
+
+	// Renaming is per metric
+	plain := attrsByMetric["orders_plain_total"]
+	if !plain.HasValue("order_status") || plain.HasValue("status") {
+		t.Errorf("orders_plain_total: expected original key order_status, got %v", plain.ToSlice())
+	}
+}
+
+func TestMetricAttributeRename_AggregatedGaugeValueKey(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	queueSampled := capitan.NewSignal("queue.sampled", "Queue Sampled")
+	depthKey := capitan.NewInt64Key("depth")
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{
+				Signal:          "queue.sampled",
+				Name:            "queue_depth_max",
+				Type:            "gauge",
+				ValueKey:        "depth",
+				Aggregation:     "max",
+				AttributeRename: map[string]string{"depth": "queue_depth"},
+			},
+		},
+	}
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	if err := sh.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	cap.Emit(ctx, queueSampled, depthKey.Field(int64(3)))
+	cap.Emit(ctx, queueSampled, depthKey.Field(int64(7)))
+	time.Sleep(100 * time.Millisecond)
+
+	// The renamed value attribute is still dropped, so both samples aggregate together
+	got, ok := collectInt64Gauge(t, reader, "queue_depth_max")
+	if !ok {
+		t.Fatal("expected a single aggregated data point")
+	}
+	if got != 7 {
+		t.Errorf("queue_depth_max = %d, want 7", got)
+	}
+}
+
+func TestMetricAttributeAllowlist(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	orderCreated := capitan.NewSignal("order.created", "Order Created")
+	totalKey := capitan.NewFloat64Key("total")
+	statusKey := capitan.NewStringKey("order_status")
+	userKey := capitan.NewStringKey("user_id")
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{
+				Signal:             "order.created",
+				Name:               "orders_total",
+				Type:               "counter",
+				AttributeAllowlist: []string{"order_status"},
+				AttributeRename:    map[string]string{"order_status": "status"},
+			},
+			{
+				Signal:             "order.created",
+				Name:               "order_value",
+				Type:               "histogram",
+				ValueKey:           "total",
+				AttributeAllowlist: []string{"order_status"},
+			},
+			{
+				Signal: "order.created",
+				Name:   "orders_all_total",
+				Type:   "counter",
+			},
+		},
+	}
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	if err := sh.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	cap.Emit(ctx, orderCreated, totalKey.Field(10.0), statusKey.Field("paid"), userKey.Field("user-1"))
+	cap.Emit(ctx, orderCreated, totalKey.Field(20.0), statusKey.Field("paid"), userKey.Field("user-2"))
+	time.Sleep(100 * time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	pointsByMetric := make(map[string][]attribute.Set)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					pointsByMetric[m.Name] = append(pointsByMetric[m.Name], dp.Attributes)
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					if dp.Count > 0 {
+						pointsByMetric[m.Name] = append(pointsByMetric[m.Name], dp.Attributes)
+					}
+				}
+			}
+		}
+	}
+
+	// Dropping user_id collapses both events into one series
+	for _, name := range []string{"orders_total", "order_value_f64"} {
+		points := pointsByMetric[name]
+		if len(points) != 1 {
+			t.Errorf("%s: expected 1 data point, got %d", name, len(points))
+			continue
+		}
+		if points[0].HasValue("user_id") || points[0].HasValue("total") {
+			t.Errorf("%s: unexpected attributes %v", name, points[0].ToSlice())
+		}
+	}
+
+	// Allowlist matches the source key; rename applies afterwards
+	if points := pointsByMetric["orders_total"]; len(points) == 1 && !points[0].HasValue("status") {
+		t.Errorf("orders_total: expected renamed status attribute, got %v", points[0].ToSlice())
+	}
+
+	// Empty allowlist keeps every field
+	if points := pointsByMetric["orders_all_total"]; len(points) != 2 {
+		t.Errorf("orders_all_total: expected 2 data points, got %d", len(points))
+	}
+}
+
+func TestMetricExcludeFields(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	reader := apertesting.NewManualMetricReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader.Reader()))
+	defer meterProvider.Shutdown(ctx)
+
+	orderCreated := capitan.NewSignal("order.created", "Order Created")
+	totalKey := capitan.NewFloat64Key("total")
+	statusKey := capitan.NewStringKey("order_status")
+	userKey := capitan.NewStringKey("user_id")
+
+	schema := Schema{
+		ExcludeFields: []string{"user_id", "total"},
+		Metrics: []MetricSchema{
+			{Signal: "order.created", Name: "orders_total", Type: "counter"},
+			{
+				Signal:             "order.created",
+				Name:               "order_value",
+				Type:               "histogram",
+				ValueKey:           "total",
+				AttributeAllowlist: []string{"order_status", "user_id"},
+			},
+		},
+	}
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	if err := sh.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	cap.Emit(ctx, orderCreated, totalKey.Field(10.0), statusKey.Field("paid"), userKey.Field("user-1"))
+	cap.Emit(ctx, orderCreated, totalKey.Field(20.0), statusKey.Field("paid"), userKey.Field("user-2"))
+	sh.Flush(ctx)
+
+	// Excluded fields never become dimensions, even when allowlisted
+	for _, name := range []string{"orders_total", "order_value_f64"} {
+		m, ok := reader.Metric(ctx, name)
+		if !ok {
+			t.Errorf("%s not recorded", name)
+			continue
+		}
+		if len(m.DataPoints) != 1 {
+			t.Errorf("%s: expected 1 data point, got %d", name, len(m.DataPoints))
+			continue
+		}
+		attrs := m.DataPoints[0].Attributes
+		if _, ok := attrs["user_id"]; ok {
+			t.Errorf("%s: excluded user_id kept: %v", name, attrs)
+		}
+		if _, ok := attrs["total"]; ok {
+			t.Errorf("%s: excluded total kept: %v", name, attrs)
+		}
+		if attrs["order_status"] != "paid" {
+			t.Errorf("%s: order_status = %q, want paid", name, attrs["order_status"])
+		}
+	}
+
+	// An excluded field still supplies the metric value
+	if m, ok := reader.Metric(ctx, "order_value_f64"); ok && m.Total() != 30 {
+		t.Errorf("order_value_f64 sum = %v, want 30", m.Total())
+	}
+}
+
+func TestMetricIncludeSeverityAttribute(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	jobDone := capitan.NewSignal("job.done", "Job done")
+	durationKey := capitan.NewDurationKey("duration")
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{
+				Signal:                   "job.done",
+				Name:                     "jobs_total",
+				Type:                     "counter",
+				IncludeSeverityAttribute: true,
+			},
+			{
+				Signal:                   "job.done",
+				Name:                     "job_duration",
+				Type:                     "histogram",
+				ValueKey:                 "duration",
+				IncludeSeverityAttribute: true,
+			},
+			{
+				Signal: "job.done",
+				Name:   "jobs_plain_total",
+				Type:   "counter",
+			},
+		},
+	}
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	if err := sh.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	cap.Info(ctx, jobDone, durationKey.Field(time.Second))
+	cap.Error(ctx, jobDone, durationKey.Field(time.Second))
+	cap.Error(ctx, jobDone, durationKey.Field(time.Second))
+	time.Sleep(100 * time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	// metric name → severity → count
+	counts := make(map[string]map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			add := func(attrs attribute.Set, n int64) {
+				if counts[m.Name] == nil {
+					counts[m.Name] = make(map[string]int64)
+				}
+				sev, _ := attrs.Value(severityAttributeKey)
+				counts[m.Name][sev.AsString()] += n
+			}
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					add(dp.Attributes, dp.Value)
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					add(dp.Attributes, int64(dp.Count))
+				}
+			}
+		}
+	}
+
+	for _, name := range []string{"jobs_total", "job_duration_f64"} {
+		if got := counts[name]["INFO"]; got != 1 {
+			t.Errorf("%s{severity=INFO} = %d, want 1", name, got)
+		}
+		if got := counts[name]["ERROR"]; got != 2 {
+			t.Errorf("%s{severity=ERROR} = %d, want 2", name, got)
+		}
+	}
+
+	// Off by default
+	if got := counts["jobs_plain_total"][""]; got != 3 {
+		t.Errorf("jobs_plain_total without severity = %d, want 3", got)
+	}
+}
+
+func TestMetricIncludeSignalAttribute(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	orderPlaced := capitan.NewSignal("order.placed", "Order placed")
+	orderShipped := capitan.NewSignal("order.shipped", "Order shipped")
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{Signal: "order.placed", Name: "orders_placed_total", Type: "counter", IncludeSignalAttribute: true},
+			{Signal: "order.shipped", Name: "orders_shipped_total", Type: "counter", IncludeSignalAttribute: true},
+			{Signal: "order.placed", Name: "orders_plain_total", Type: "counter"},
+		},
+	}
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	if err := sh.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	cap.Emit(ctx, orderPlaced)
+	cap.Emit(ctx, orderPlaced)
+	cap.Emit(ctx, orderShipped)
+	time.Sleep(100 * time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	// metric name → signal → count
+	counts := make(map[string]map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			data, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				continue
+			}
+			for _, dp := range data.DataPoints {
+				if counts[m.Name] == nil {
+					counts[m.Name] = make(map[string]int64)
+				}
+				sig, _ := dp.Attributes.Value(signalAttributeKey)
+				counts[m.Name][sig.AsString()] += dp.Value
+			}
+		}
+	}
+
+	if got := counts["orders_placed_total"]["order.placed"]; got != 2 {
+		t.Errorf("orders_placed_total{signal=order.placed} = %d, want 2", got)
+	}
+	if got := counts["orders_shipped_total"]["order.shipped"]; got != 1 {
+		t.Errorf("orders_shipped_total{signal=order.shipped} = %d, want 1", got)
+	}
+
+	// Off by default
+	if got := counts["orders_plain_total"][""]; got != 2 {
+		t.Errorf("orders_plain_total without signal = %d, want 2", got)
+	}
+}
+
+func TestMetricFilterKey(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	orderCompleted := capitan.NewSignal("order.completed", "Order Completed")
+	successKey := capitan.NewBoolKey("success")
+	statusKey := capitan.NewStringKey("status")
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{Signal: "order.completed", Name: "orders_total"},
+			// Boolean filter defaults to matching true
+			{Signal: "order.completed", Name: "successful_orders_total", FilterKey: "success"},
+			{Signal: "order.completed", Name: "refunded_orders_total", FilterKey: "status", FilterValue: "refunded"},
+		},
+	}
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	if err := sh.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	cap.Emit(ctx, orderCompleted, successKey.Field(true), statusKey.Field("paid"))
+	cap.Emit(ctx, orderCompleted, successKey.Field(true), statusKey.Field("paid"))
+	cap.Emit(ctx, orderCompleted, successKey.Field(false), statusKey.Field("refunded"))
+	// Missing filter fields skip the filtered metrics
+	cap.Emit(ctx, orderCompleted)
+
+	time.Sleep(100 * time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	totals := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if data, ok := m.Data.(metricdata.Sum[int64]); ok {
+				for _, dp := range data.DataPoints {
+					totals[m.Name] += dp.Value
+				}
+			}
+		}
+	}
+
+	want := map[string]int64{
+		"orders_total":            4,
+		"successful_orders_total": 2,
+		"refunded_orders_total":   1,
+	}
+	for name, n := range want {
+		if totals[name] != n {
+			t.Errorf("%s = %d, want %d", name, totals[name], n)
+		}
+	}
+}
+
+func TestMetricHistogramBuckets(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	requestDone := capitan.NewSignal("request.done", "Request Done")
+	latencyKey := capitan.NewInt64Key("latency_ms")
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{Signal: "request.done", Name: "latency_preset", Type: "histogram", ValueKey: "latency_ms", BucketsPreset: "http_latency_ms"},
+			// Explicit buckets win over the preset
+			{Signal: "request.done", Name: "latency_explicit", Type: "histogram", ValueKey: "latency_ms", BucketsPreset: "bytes", Buckets: []float64{10, 100}},
+			{Signal: "request.done", Name: "latency_default", Type: "histogram", ValueKey: "latency_ms"},
+		},
+	}
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	if err := sh.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	cap.Emit(ctx, requestDone, latencyKey.Field(42))
+	time.Sleep(100 * time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	bounds := make(map[string][]float64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if data, ok := m.Data.(metricdata.Histogram[int64]); ok && len(data.DataPoints) > 0 {
+				bounds[m.Name] = data.DataPoints[0].Bounds
+			}
+		}
+	}
+
+	if got, want := bounds["latency_preset"], bucketPresets["http_latency_ms"]; !slices.Equal(got, want) {
+		t.Errorf("latency_preset bounds = %v, want %v", got, want)
+	}
+	if got, want := bounds["latency_explicit"], []float64{10, 100}; !slices.Equal(got, want) {
+		t.Errorf("latency_explicit bounds = %v, want %v", got, want)
+	}
+	if got := bounds["latency_default"]; len(got) == 0 || slices.Equal(got, bucketPresets["http_latency_ms"]) {
+		t.Errorf("latency_default bounds = %v, want the SDK defaults", got)
+	}
+}
+
+func TestMetricUint64BeyondMaxInt64(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	diskUsage := capitan.NewSignal("disk.usage", "Disk Usage")
+	bytesKey := capitan.NewUint64Key("bytes")
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{Signal: "disk.usage", Name: "disk_bytes", Type: "gauge", ValueKey: "bytes"},
+		},
+	}
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	if err := sh.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	cap.Emit(ctx, diskUsage, bytesKey.Field(uint64(math.MaxUint64)))
+	time.Sleep(100 * time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	var got float64
+	var found bool
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Gauge[int64]:
+				t.Errorf("%s recorded on the int64 instrument: %v", m.Name, data.DataPoints)
+			case metricdata.Gauge[float64]:
+				if len(data.DataPoints) > 0 {
+					got, found = data.DataPoints[0].Value, true
+				}
+			}
+		}
+	}
+
+	// Magnitude is kept rather than clamped to math.MaxInt64
+	if !found {
+		t.Fatal("expected a float64 gauge data point")
+	}
+	if got != float64(uint64(math.MaxUint64)) {
+		t.Errorf("disk_bytes = %v, want %v", got, float64(uint64(math.MaxUint64)))
+	}
+}
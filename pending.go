@@ -0,0 +1,121 @@
+package aperture
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// PendingEvent is a trace start or end event waiting for its counterpart.
+type PendingEvent struct {
+	// Time is the event's own timestamp, used as the span start or end.
+	Time time.Time
+
+	// ReceivedAt is when aperture stored the event. Events older than
+	// Timeout are expired by the periodic cleanup.
+	ReceivedAt time.Time
+
+	// Context is the event's context, used as the span parent and for context
+	// extraction. It cannot be persisted; a store may return nil, in which case
+	// context.Background() is used.
+	Context context.Context
+
+	// SpanName is the configured span name.
+	SpanName string
+
+	// CorrelationID is the correlation value extracted from the event.
+	CorrelationID string
+
+	// Timeout is the span timeout of the event's trace config. Zero uses the
+	// longest timeout across the current trace configs.
+	Timeout time.Duration
+
+	// Unsampled marks a start that was sampled out; its matching end is
+	// dropped without creating a span.
+	Unsampled bool
+
+	// Ended marks a span already closed by one of several end signals of its
+	// trace config. The marker is kept until its timeout so that a later end
+	// is reported as a duplicate instead of waiting for a start.
+	Ended bool
+}
+
+// PendingStore holds pending trace events by composite key (correlation ID plus
+// start and end signal names).
+//
+// The default store is an in-memory map. A custom store, e.g. one backed by
+// Redis, lets long-lived spans correlate across process restarts. Set stores
+// with [Aperture.SetPendingStores].
+//
+// Implementations must be safe for concurrent use: the same store is shared by
+// the traces handler built on each Apply.
+type PendingStore interface {
+	// Get returns the event stored under key.
+	Get(key string) (PendingEvent, bool)
+
+	// Set stores ev under key, replacing any existing event.
+	Set(key string, ev PendingEvent)
+
+	// Delete removes the event stored under key, if any.
+	Delete(key string)
+
+	// Range calls fn for each stored event until fn returns false.
+	// fn must not call other methods of the store.
+	Range(fn func(key string, ev PendingEvent) bool)
+
+	// Len returns the number of stored events. Used for max_pending and the
+	// pending span counts.
+	Len() int
+}
+
+// memoryPendingStore is the default in-memory PendingStore.
+type memoryPendingStore struct {
+	events map[string]PendingEvent
+	mu     sync.Mutex
+}
+
+// NewMemoryPendingStore returns the default in-memory [PendingStore].
+// Its contents are lost when the process exits.
+func NewMemoryPendingStore() PendingStore {
+	return &memoryPendingStore{events: make(map[string]PendingEvent)}
+}
+
+// Get implements PendingStore.
+func (m *memoryPendingStore) Get(key string) (PendingEvent, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ev, ok := m.events[key]
+	return ev, ok
+}
+
+// Set implements PendingStore.
+func (m *memoryPendingStore) Set(key string, ev PendingEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events[key] = ev
+}
+
+// Delete implements PendingStore.
+func (m *memoryPendingStore) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.events, key)
+}
+
+// Range implements PendingStore.
+func (m *memoryPendingStore) Range(fn func(key string, ev PendingEvent) bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, ev := range m.events {
+		if !fn(key, ev) {
+			return
+		}
+	}
+}
+
+// Len implements PendingStore.
+func (m *memoryPendingStore) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.events)
+}
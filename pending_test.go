@@ -0,0 +1,169 @@
+package aperture
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apertesting "github.com/zoobzio/aperture/testing"
+	"github.com/zoobzio/capitan"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestMemoryPendingStore(t *testing.T) {
+	store := NewMemoryPendingStore()
+
+	if _, ok := store.Get("a"); ok {
+		t.Error("expected empty store")
+	}
+
+	store.Set("a", PendingEvent{SpanName: "first"})
+	store.Set("b", PendingEvent{SpanName: "second"})
+	store.Set("a", PendingEvent{SpanName: "replaced"})
+
+	if got := store.Len(); got != 2 {
+		t.Errorf("Len() = %d, want 2", got)
+	}
+	if ev, ok := store.Get("a"); !ok || ev.SpanName != "replaced" {
+		t.Errorf("Get(a) = %+v, %v, want replaced", ev, ok)
+	}
+
+	seen := 0
+	store.Range(func(string, PendingEvent) bool {
+		seen++
+		return false
+	})
+	if seen != 1 {
+		t.Errorf("Range visited %d events after returning false, want 1", seen)
+	}
+
+	store.Delete("a")
+	store.Delete("missing")
+	if got := store.Len(); got != 1 {
+		t.Errorf("Len() after Delete = %d, want 1", got)
+	}
+}
+
+func TestPendingStore_SurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+
+	recorder := tracetest.NewSpanRecorder()
+	traceProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer traceProvider.Shutdown(ctx)
+
+	requestStarted := capitan.NewSignal("request.started", "Request Started")
+	requestCompleted := capitan.NewSignal("request.completed", "Request Completed")
+	requestIDKey := capitan.NewStringKey("request_id")
+
+	schema := Schema{
+		Traces: []TraceSchema{
+			{
+				Start:          "request.started",
+				End:            "request.completed",
+				CorrelationKey: "request_id",
+				SpanName:       "http_request",
+			},
+		},
+	}
+
+	starts, ends := NewMemoryPendingStore(), NewMemoryPendingStore()
+	startTime := time.Now().Add(-time.Minute)
+
+	// First process: the start event is stored, then the process exits
+	cap1 := capitan.New()
+	sh1, err := New(cap1, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), traceProvider)
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	sh1.SetPendingStores(starts, ends)
+	if err := sh1.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	cap1.Emit(ctx, requestStarted, requestIDKey.Field("REQ-1"))
+	time.Sleep(100 * time.Millisecond)
+	sh1.Close()
+
+	if got := starts.Len(); got != 1 {
+		t.Fatalf("expected the start to outlive Close, got %d pending", got)
+	}
+
+	// Simulate a reload from persistent storage: contexts are lost
+	key := "REQ-1:request.started:request.completed"
+	pending, ok := starts.Get(key)
+	if !ok {
+		t.Fatalf("expected pending start under %q", key)
+	}
+	pending.Context = nil
+	pending.Time = startTime
+	starts.Set(key, pending)
+
+	// Second process: the end event completes the span
+	cap2 := capitan.New()
+	sh2, err := New(cap2, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), traceProvider)
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh2.Close()
+	sh2.SetPendingStores(starts, ends)
+	if err := sh2.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	cap2.Emit(ctx, requestCompleted, requestIDKey.Field("REQ-1"))
+	time.Sleep(100 * time.Millisecond)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span across the restart, got %d", len(spans))
+	}
+	if spans[0].Name() != "http_request" {
+		t.Errorf("span name = %q, want http_request", spans[0].Name())
+	}
+	if !spans[0].StartTime().Equal(startTime) {
+		t.Errorf("span start = %v, want the stored start %v", spans[0].StartTime(), startTime)
+	}
+	if got := starts.Len(); got != 0 {
+		t.Errorf("expected the start to be consumed, %d pending", got)
+	}
+}
+
+func TestPendingStores_AppliedWithUnchangedSchema(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	requestStarted := capitan.NewSignal("request.started", "Request Started")
+	requestIDKey := capitan.NewStringKey("request_id")
+
+	schema := Schema{
+		Traces: []TraceSchema{
+			{Start: "request.started", End: "request.completed", CorrelationKey: "request_id"},
+		},
+	}
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	if err := sh.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	// The stores are read when the observer is built, so the same schema
+	// must still rebuild it
+	starts, ends := NewMemoryPendingStore(), NewMemoryPendingStore()
+	sh.SetPendingStores(starts, ends)
+	if err := sh.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	cap.Emit(ctx, requestStarted, requestIDKey.Field("REQ-1"))
+	sh.Flush(ctx)
+
+	if got := starts.Len(); got != 1 {
+		t.Errorf("expected the start in the new store, got %d pending", got)
+	}
+}
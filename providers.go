@@ -2,11 +2,16 @@ package aperture
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
+	"go.opentelemetry.io/otel/attribute"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
 )
 
 // Providers holds OTEL SDK providers for logs, metrics, and traces.
@@ -65,3 +70,122 @@ func (p *Providers) Shutdown(ctx context.Context) error {
 
 	return nil
 }
+
+// Healthy force-flushes every provider and reports whether the buffered
+// telemetry reached its exporters, e.g. for a /readyz endpoint.
+//
+// For batched exporters, healthy means the last forced export succeeded. A
+// provider with nothing buffered may not contact its collector at all and is
+// then reported healthy, so an idle process only notices an unreachable
+// endpoint once it has telemetry to send. Exporters with retries enabled block
+// until they give up, so pass a context with a short deadline.
+//
+// Returns nil if all present providers flushed cleanly.
+func (p *Providers) Healthy(ctx context.Context) error {
+	var errs []error
+
+	if p.Trace != nil {
+		if err := p.Trace.ForceFlush(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("trace provider: %w", err))
+		}
+	}
+
+	if p.Meter != nil {
+		if err := p.Meter.ForceFlush(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("meter provider: %w", err))
+		}
+	}
+
+	if p.Log != nil {
+		if err := p.Log.ForceFlush(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("log provider: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// HistogramViews returns meter provider views that apply base-2 exponential
+// aggregation to every histogram in schema with histogram_type "exponential".
+//
+// Aggregation is selected by the meter provider, not the instrument, so these
+// views must be passed when the provider is created. Histograms configured
+// later (e.g. by a hot-reload) keep the provider's default aggregation until
+// the provider is rebuilt.
+//
+// Example:
+//
+//	meterProvider := sdkmetric.NewMeterProvider(
+//	    sdkmetric.WithReader(reader),
+//	    sdkmetric.WithView(aperture.HistogramViews(schema)...),
+//	)
+func HistogramViews(schema Schema) []sdkmetric.View {
+	var views []sdkmetric.View
+	for _, m := range schema.Metrics {
+		if m.Type != string(MetricTypeHistogram) || HistogramType(m.HistogramType) != HistogramTypeExponential {
+			continue
+		}
+
+		// Each histogram is backed by an int64 and a float64 instrument
+		for _, name := range []string{m.Name, m.Name + "_f64"} {
+			views = append(views, sdkmetric.NewView(
+				sdkmetric.Instrument{Name: name, Kind: sdkmetric.InstrumentKindHistogram},
+				sdkmetric.Stream{Aggregation: sdkmetric.AggregationBase2ExponentialHistogram{
+					MaxSize:  160,
+					MaxScale: 20,
+				}},
+			))
+		}
+	}
+	return views
+}
+
+// WithServiceInfo returns a resource identifying the service, merged over
+// [resource.Default] so SDK and environment attributes are kept.
+//
+// Pass the result to each provider so every log, metric, and span carries
+// service.name and service.version.
+//
+// Example:
+//
+//	res, err := aperture.WithServiceInfo("checkout", "v1.4.0")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	traceProvider := sdktrace.NewTracerProvider(sdktrace.WithResource(res), ...)
+func WithServiceInfo(name, version string) (*resource.Resource, error) {
+	if name == "" {
+		return nil, fmt.Errorf("service name is required")
+	}
+
+	attrs := []attribute.KeyValue{semconv.ServiceName(name)}
+	if version != "" {
+		attrs = append(attrs, semconv.ServiceVersion(version))
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attrs...))
+	if err != nil {
+		return nil, fmt.Errorf("creating resource: %w", err)
+	}
+	return res, nil
+}
+
+// ValidateResource returns an error if res lacks a resolvable service.name.
+//
+// The SDK fills in "unknown_service:<executable>" when no name is configured;
+// that placeholder is treated as missing, since backends group all such
+// services together.
+func ValidateResource(res *resource.Resource) error {
+	if res == nil {
+		return errors.New("resource is nil")
+	}
+
+	name, ok := res.Set().Value(semconv.ServiceNameKey)
+	if !ok || name.AsString() == "" {
+		return fmt.Errorf("%s is not set", semconv.ServiceNameKey)
+	}
+	if strings.HasPrefix(name.AsString(), "unknown_service") {
+		return fmt.Errorf("%s is the SDK default %q", semconv.ServiceNameKey, name.AsString())
+	}
+	return nil
+}
@@ -2,11 +2,21 @@ package aperture
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	apertesting "github.com/zoobzio/aperture/testing"
+	"github.com/zoobzio/capitan"
+	"go.opentelemetry.io/otel/attribute"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestProviders_Shutdown(t *testing.T) {
@@ -115,3 +125,187 @@ func TestProviders_DoubleShutdown(t *testing.T) {
 		t.Error("Expected error on double shutdown, got nil")
 	}
 }
+
+// failingSpanExporter rejects every export, like an unreachable collector.
+type failingSpanExporter struct{}
+
+func (failingSpanExporter) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) error {
+	return errors.New("connection refused")
+}
+
+func (failingSpanExporter) Shutdown(context.Context) error { return nil }
+
+func TestProviders_Healthy(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("exporters reachable", func(t *testing.T) {
+		pvs := &Providers{
+			Log:   sdklog.NewLoggerProvider(),
+			Meter: sdkmetric.NewMeterProvider(),
+			Trace: sdktrace.NewTracerProvider(sdktrace.WithBatcher(tracetest.NewInMemoryExporter())),
+		}
+		defer pvs.Shutdown(ctx)
+
+		_, span := pvs.Trace.Tracer("test").Start(ctx, "op")
+		span.End()
+
+		if err := pvs.Healthy(ctx); err != nil {
+			t.Errorf("Healthy() = %v, want nil", err)
+		}
+	})
+
+	t.Run("trace exporter unreachable", func(t *testing.T) {
+		pvs := &Providers{
+			Trace: sdktrace.NewTracerProvider(sdktrace.WithBatcher(failingSpanExporter{})),
+		}
+		defer pvs.Shutdown(ctx)
+
+		_, span := pvs.Trace.Tracer("test").Start(ctx, "op")
+		span.End()
+
+		err := pvs.Healthy(ctx)
+		if err == nil {
+			t.Fatal("Healthy() = nil, want an error")
+		}
+		if !strings.Contains(err.Error(), "trace provider: connection refused") {
+			t.Errorf("Healthy() = %q, want a trace provider error", err)
+		}
+	})
+
+	t.Run("all providers nil", func(t *testing.T) {
+		pvs := &Providers{}
+		if err := pvs.Healthy(ctx); err != nil {
+			t.Errorf("Healthy() = %v, want nil", err)
+		}
+	})
+}
+
+func TestWithServiceInfo(t *testing.T) {
+	res, err := WithServiceInfo("checkout", "v1.4.0")
+	if err != nil {
+		t.Fatalf("WithServiceInfo failed: %v", err)
+	}
+
+	if v, _ := res.Set().Value("service.name"); v.AsString() != "checkout" {
+		t.Errorf("service.name = %q, want 'checkout'", v.AsString())
+	}
+	if v, _ := res.Set().Value("service.version"); v.AsString() != "v1.4.0" {
+		t.Errorf("service.version = %q, want 'v1.4.0'", v.AsString())
+	}
+	// SDK defaults are kept
+	if _, ok := res.Set().Value("telemetry.sdk.name"); !ok {
+		t.Error("expected default SDK attributes to be merged")
+	}
+
+	if err := ValidateResource(res); err != nil {
+		t.Errorf("ValidateResource() = %v, want nil", err)
+	}
+
+	if _, err := WithServiceInfo("", "v1"); err == nil {
+		t.Error("expected error for empty service name")
+	}
+}
+
+func TestValidateResource(t *testing.T) {
+	tests := []struct {
+		name    string
+		res     *resource.Resource
+		wantErr bool
+	}{
+		{"nil resource", nil, true},
+		{"empty resource", resource.Empty(), true},
+		{"sdk default", resource.Default(), true},
+		{"empty name", resource.NewSchemaless(attribute.String("service.name", "")), true},
+		{"named", resource.NewSchemaless(attribute.String("service.name", "orders")), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateResource(tt.res)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateResource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHistogramViews(t *testing.T) {
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{Signal: "request.done", Name: "latency_exp", Type: "histogram", ValueKey: "duration", HistogramType: "exponential"},
+			{Signal: "request.done", Name: "latency", Type: "histogram", ValueKey: "duration"},
+			{Signal: "request.done", Name: "requests_total", Type: "counter"},
+		},
+	}
+
+	if views := HistogramViews(Schema{}); len(views) != 0 {
+		t.Errorf("expected no views for an empty schema, got %d", len(views))
+	}
+
+	// One view each for the int64 and float64 instruments
+	if views := HistogramViews(schema); len(views) != 2 {
+		t.Errorf("expected 2 views, got %d", len(views))
+	}
+}
+
+func TestHistogramViews_Aggregation(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{Signal: "request.done", Name: "latency_exp", Type: "histogram", ValueKey: "duration", HistogramType: "exponential"},
+			{Signal: "request.done", Name: "latency", Type: "histogram", ValueKey: "duration", HistogramType: "explicit"},
+		},
+	}
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(reader),
+		sdkmetric.WithView(HistogramViews(schema)...),
+	)
+	defer meterProvider.Shutdown(ctx)
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	if err := sh.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	requestDone := capitan.NewSignal("request.done", "Request done")
+	durationKey := capitan.NewDurationKey("duration")
+	cap.Emit(ctx, requestDone, durationKey.Field(150*time.Millisecond))
+	time.Sleep(100 * time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	aggregations := make(map[string]string)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.ExponentialHistogram[float64]:
+				if len(data.DataPoints) > 0 && data.DataPoints[0].Count > 0 {
+					aggregations[m.Name] = "exponential"
+				}
+			case metricdata.Histogram[float64]:
+				if len(data.DataPoints) > 0 && data.DataPoints[0].Count > 0 {
+					aggregations[m.Name] = "explicit"
+				}
+			}
+		}
+	}
+
+	if got := aggregations["latency_exp_f64"]; got != "exponential" {
+		t.Errorf("latency_exp_f64 aggregation = %q, want exponential", got)
+	}
+	if got := aggregations["latency_f64"]; got != "explicit" {
+		t.Errorf("latency_f64 aggregation = %q, want explicit", got)
+	}
+}
@@ -1,8 +1,18 @@
 package aperture
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"math"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -25,6 +35,52 @@ func LoadSchemaFromJSON(data []byte) (Schema, error) {
 	return s, nil
 }
 
+// LoadSchemaFromYAMLStrict is like [LoadSchemaFromYAML] but fails on keys that
+// do not match a schema field, so a typo such as "wihtelist" is reported
+// instead of silently producing an empty config.
+func LoadSchemaFromYAMLStrict(data []byte) (Schema, error) {
+	var s Schema
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil && !errors.Is(err, io.EOF) {
+		return Schema{}, fmt.Errorf("yaml unmarshal: %w", err)
+	}
+	return s, nil
+}
+
+// LoadSchemaFromJSONStrict is like [LoadSchemaFromJSON] but fails on keys that
+// do not match a schema field.
+func LoadSchemaFromJSONStrict(data []byte) (Schema, error) {
+	var s Schema
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&s); err != nil {
+		return Schema{}, fmt.Errorf("json unmarshal: %w", err)
+	}
+	if dec.More() {
+		return Schema{}, errors.New("json unmarshal: unexpected data after schema")
+	}
+	return s, nil
+}
+
+// LoadSchemaFromReader reads a Schema in the given format ("yaml", "yml", or
+// "json") from r.
+func LoadSchemaFromReader(r io.Reader, format string) (Schema, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Schema{}, fmt.Errorf("reading schema: %w", err)
+	}
+
+	switch strings.ToLower(format) {
+	case "yaml", "yml":
+		return LoadSchemaFromYAML(data)
+	case "json":
+		return LoadSchemaFromJSON(data)
+	default:
+		return Schema{}, fmt.Errorf("unsupported schema format %q", format)
+	}
+}
+
 // Schema is the serializable configuration for aperture.
 // Load from YAML or JSON via [LoadSchemaFromYAML] or [LoadSchemaFromJSON], then apply via [Aperture.Apply].
 type Schema struct {
@@ -43,8 +99,80 @@ type Schema struct {
 	// Traces specifies signal pairs that should be correlated into spans.
 	Traces []TraceSchema `json:"traces,omitempty" yaml:"traces,omitempty"`
 
+	// ExcludeFields lists event field keys dropped before fields become log
+	// attributes, metric attributes, or stdout attributes. Empty keeps every
+	// field. Excluded fields can still be used as metric values and trace
+	// correlation keys.
+	ExcludeFields []string `json:"exclude_fields,omitempty" yaml:"exclude_fields,omitempty"`
+
+	// UnusedConfigWarmup enables the unused config report (e.g., "10m").
+	// If a configured metric or trace signal is not seen within this window,
+	// SignalConfigUnused is emitted for it. Disabled if empty.
+	UnusedConfigWarmup string `json:"unused_config_warmup,omitempty" yaml:"unused_config_warmup,omitempty"`
+
+	// MaxPending caps the number of pending trace starts, and separately pending
+	// ends, held while waiting for their counterpart. Once a cap is reached, new
+	// events are rejected with SignalTracePendingOverflow; events already pending
+	// are kept. Zero (default) means no cap.
+	MaxPending int `json:"max_pending,omitempty" yaml:"max_pending,omitempty"`
+
+	// MaxAttributeBytes truncates string and bytes field values longer than
+	// this many bytes in log and metric attributes. Truncated values end with
+	// "..." and the record gets an aperture.truncated attribute. Zero
+	// (default) means no limit.
+	MaxAttributeBytes int `json:"max_attribute_bytes,omitempty" yaml:"max_attribute_bytes,omitempty"`
+
+	// StdoutFormat is the stdout log format: "text" (default) or "json".
+	// Only used when Stdout is true.
+	StdoutFormat string `json:"stdout_format,omitempty" yaml:"stdout_format,omitempty"`
+
+	// ScopeName is the instrumentation scope of event logs, metrics, and spans,
+	// so several Aperture instances sharing providers stay apart in the
+	// backend. Diagnostics and aperture's own metrics use "<scope_name>.internal".
+	// Defaults to "capitan", with diagnostics on "aperture.internal".
+	ScopeName string `json:"scope_name,omitempty" yaml:"scope_name,omitempty"`
+
 	// Stdout enables duplication of OTEL output to stdout.
 	Stdout bool `json:"stdout,omitempty" yaml:"stdout,omitempty"`
+
+	// StrictWhitelist gates metrics and traces by the log whitelist as well,
+	// so only whitelisted signals produce any telemetry. Has no effect unless
+	// logs.whitelist is set. Defaults to false (metrics and traces are
+	// independent of log filtering).
+	StrictWhitelist bool `json:"strict_whitelist,omitempty" yaml:"strict_whitelist,omitempty"`
+
+	// BestEffort skips a metric whose instrument the meter provider fails to
+	// create, emitting SignalConfigError, while the other metrics are created.
+	// Defaults to false: any instrument error fails Apply and keeps the
+	// previous configuration.
+	BestEffort bool `json:"best_effort,omitempty" yaml:"best_effort,omitempty"`
+
+	// PendingSpanMetric exports the number of trace start and end events waiting
+	// for their counterpart as the aperture_trace_pending_spans gauge, with a
+	// kind attribute of "start" or "end". Defaults to false.
+	PendingSpanMetric bool `json:"pending_span_metric,omitempty" yaml:"pending_span_metric,omitempty"`
+
+	// LogsEmittedMetric counts forwarded log records in the
+	// aperture_logs_emitted_total counter, with a signal attribute.
+	// Defaults to false.
+	LogsEmittedMetric bool `json:"logs_emitted_metric,omitempty" yaml:"logs_emitted_metric,omitempty"`
+
+	// KeepUnserializable emits custom-typed fields that fail JSON serialization
+	// as a "<unserializable:Type>" placeholder in logs and metric attributes.
+	// Defaults to false: such fields are dropped. SignalTransformFailed is
+	// emitted either way.
+	KeepUnserializable bool `json:"keep_unserializable,omitempty" yaml:"keep_unserializable,omitempty"`
+
+	// SortAttributes emits log record and span attributes sorted by key, so
+	// output is byte-for-byte stable for golden-file tests. Metric attributes
+	// are always sorted by the SDK's attribute sets. Defaults to false, which
+	// skips the sort and keeps emission order.
+	SortAttributes bool `json:"sort_attributes,omitempty" yaml:"sort_attributes,omitempty"`
+
+	// MirrorMetaAttributes copies each event's severity and timestamp into the
+	// event.severity and event.timestamp log attributes, for backends that
+	// don't index the native OTEL fields. Defaults to false.
+	MirrorMetaAttributes bool `json:"mirror_meta_attributes,omitempty" yaml:"mirror_meta_attributes,omitempty"`
 }
 
 // MetricSchema defines a signal-to-metric conversion in serializable form.
@@ -65,6 +193,76 @@ type MetricSchema struct {
 
 	// Description is optional metric description.
 	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Unit is the optional UCUM unit of the instrument, e.g. "ms", "By", or "{request}".
+	Unit string `json:"unit,omitempty" yaml:"unit,omitempty"`
+
+	// DurationUnit controls how duration values are recorded: "ms" or "ns".
+	// Defaults to "ms" (float64) for histograms and "ns" (exact int64) for
+	// gauges and updowncounters.
+	DurationUnit string `json:"duration_unit,omitempty" yaml:"duration_unit,omitempty"`
+
+	// Aggregation controls how gauge values are combined within a reporting
+	// interval: "last", "max", "min", or "sum". Only valid for gauges.
+	// Defaults to "last".
+	Aggregation string `json:"aggregation,omitempty" yaml:"aggregation,omitempty"`
+
+	// Attributes are constant attributes (e.g. tier: premium) added to every
+	// measurement of this metric without carrying them on each event.
+	Attributes map[string]string `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+
+	// ParseStringValue enables parsing a string value field (e.g. "42.5") as a
+	// float64. Unparseable strings emit a diagnostic. Defaults to false.
+	ParseStringValue bool `json:"parse_string_value,omitempty" yaml:"parse_string_value,omitempty"`
+
+	// AttributeRename maps event field keys to metric attribute names
+	// (e.g. order_status: status). Unmatched keys pass through unchanged.
+	// Only applies to event fields, not context or constant attributes.
+	AttributeRename map[string]string `json:"attribute_rename,omitempty" yaml:"attribute_rename,omitempty"`
+
+	// AttributeAllowlist names the event field keys that become metric dimensions.
+	// Other fields are dropped, which keeps high-cardinality fields (e.g. user_id)
+	// out of the metric. Keys are matched before AttributeRename is applied.
+	// If empty, all fields are kept.
+	AttributeAllowlist []string `json:"attribute_allowlist,omitempty" yaml:"attribute_allowlist,omitempty"`
+
+	// HistogramType selects histogram aggregation: "explicit" or "exponential".
+	// Only valid for histograms. Defaults to "explicit". Aggregation is owned by
+	// the meter provider, so "exponential" takes effect only when the provider
+	// is created with the views from [HistogramViews] for this schema. Views
+	// are fixed when the provider is built: changing the type on Apply or hot
+	// reload has no effect and emits SignalMetricHistogramTypeChanged.
+	HistogramType string `json:"histogram_type,omitempty" yaml:"histogram_type,omitempty"`
+
+	// Buckets are explicit histogram bucket boundaries, in increasing order.
+	// Only valid for explicit histograms. Takes precedence over BucketsPreset.
+	// If both are empty, the SDK's default boundaries are used.
+	Buckets []float64 `json:"buckets,omitempty" yaml:"buckets,omitempty"`
+
+	// BucketsPreset selects a named set of bucket boundaries:
+	// "http_latency_ms" (5ms to 10s), "latency_seconds" (0.005s to 10s), or
+	// "bytes" (64B to 16MiB). Only valid for explicit histograms.
+	BucketsPreset string `json:"buckets_preset,omitempty" yaml:"buckets_preset,omitempty"`
+
+	// IncludeSeverityAttribute adds the event's capitan severity (DEBUG, INFO,
+	// WARN, ERROR) as a "severity" attribute, e.g. for error-rate dashboards.
+	// It overrides an event field named "severity". Defaults to false.
+	IncludeSeverityAttribute bool `json:"include_severity_attribute,omitempty" yaml:"include_severity_attribute,omitempty"`
+
+	// IncludeSignalAttribute adds the event's signal name as a "signal"
+	// attribute, so one panel can group metrics fed by several signals. It
+	// overrides an event field named "signal". Defaults to false.
+	IncludeSignalAttribute bool `json:"include_signal_attribute,omitempty" yaml:"include_signal_attribute,omitempty"`
+
+	// FilterKey names an event field that must equal FilterValue for the
+	// measurement to be recorded, so one signal can drive conditional metrics
+	// (e.g. successful orders only). Events without the field are skipped with
+	// a diagnostic. If empty, every event is recorded.
+	FilterKey string `json:"filter_key,omitempty" yaml:"filter_key,omitempty"`
+
+	// FilterValue is compared with the FilterKey field's value in string form
+	// (e.g. "true", "refunded", "42"). Defaults to "true", for boolean fields.
+	FilterValue string `json:"filter_value,omitempty" yaml:"filter_value,omitempty"`
 }
 
 // TraceSchema defines a signal pair that forms a trace span in serializable form.
@@ -73,18 +271,52 @@ type TraceSchema struct {
 	Start string `json:"start" yaml:"start"`
 
 	// End is the name of the signal that completes the span.
-	End string `json:"end" yaml:"end"`
+	// Required unless Ends is set.
+	End string `json:"end,omitempty" yaml:"end,omitempty"`
+
+	// Ends lists signals that complete the span, for operations with several
+	// outcomes (e.g. request.completed and request.failed). Whichever arrives
+	// first with the matching correlation ID closes the span; a later one is
+	// reported as a duplicate. Combined with End if both are set.
+	//
+	// "First" is the order aperture handles the events, not the order they
+	// were emitted: capitan delivers each signal on its own worker, so two
+	// ends emitted close together may be handled in either order. Emit one
+	// end per operation rather than relying on which one wins.
+	Ends []string `json:"ends,omitempty" yaml:"ends,omitempty"`
 
 	// CorrelationKey is the name of the field key used to correlate start/end events.
 	CorrelationKey string `json:"correlation_key" yaml:"correlation_key"`
 
-	// SpanName is the name of the generated span.
+	// SpanName is the name of the generated span, used as is.
 	// If empty, uses the start signal name.
 	SpanName string `json:"span_name,omitempty" yaml:"span_name,omitempty"`
 
+	// SpanNameTemplate names the span from the start event's fields, using
+	// {field} placeholders such as "{method} {route}"; {{ and }} produce
+	// literal braces. If a placeholder's field is missing from the start
+	// event, SpanName (or the start signal name) is used instead.
+	SpanNameTemplate string `json:"span_name_template,omitempty" yaml:"span_name_template,omitempty"`
+
 	// SpanTimeout is the maximum duration to wait for an end event (e.g., "5m", "30s").
 	// Defaults to 5 minutes if not specified.
 	SpanTimeout string `json:"span_timeout,omitempty" yaml:"span_timeout,omitempty"`
+
+	// SampleRate is the fraction of correlated pairs that create spans (e.g., 0.1 for 10%).
+	// Sampling is deterministic per correlation ID, so start and end always agree.
+	// Defaults to 1 (every pair) if not specified.
+	SampleRate float64 `json:"sample_rate,omitempty" yaml:"sample_rate,omitempty"`
+
+	// MergeEndContext extracts context.traces values from both the start and end
+	// event contexts, with end values winning on key collision. By default only
+	// the start context is used.
+	MergeEndContext bool `json:"merge_end_context,omitempty" yaml:"merge_end_context,omitempty"`
+
+	// DurationMetric names a histogram that records each span's duration in
+	// milliseconds, with the span's attributes, when the span completes. Only
+	// sampled pairs are recorded. The name must be a valid OTEL instrument name
+	// and must not be used by another metric. Disabled if empty.
+	DurationMetric string `json:"duration_metric,omitempty" yaml:"duration_metric,omitempty"`
 }
 
 // LogSchema configures log filtering in serializable form.
@@ -92,6 +324,32 @@ type LogSchema struct {
 	// Whitelist specifies signal names to log.
 	// If empty, all signals are logged.
 	Whitelist []string `json:"whitelist,omitempty" yaml:"whitelist,omitempty"`
+
+	// Blacklist specifies signal names to never log.
+	// Takes precedence over Whitelist.
+	Blacklist []string `json:"blacklist,omitempty" yaml:"blacklist,omitempty"`
+
+	// BodyTemplate renders the log body from event fields using {field_name}
+	// placeholders (e.g. "order {order_id} created"); {{ and }} are literal
+	// braces. If an event lacks a referenced field, the signal description is
+	// used instead. Defaults to the signal description if empty.
+	BodyTemplate string `json:"body_template,omitempty" yaml:"body_template,omitempty"`
+
+	// BodyTemplates maps signal names to body templates in the BodyTemplate
+	// syntax, overriding BodyTemplate for those signals. If an event lacks a
+	// referenced field, the signal description is used instead.
+	BodyTemplates map[string]string `json:"body_templates,omitempty" yaml:"body_templates,omitempty"`
+
+	// MinFields skips logging events with fewer fields than this, such as
+	// field-less marker signals. Metrics and traces are unaffected.
+	// Defaults to 0 (log every event).
+	MinFields int `json:"min_fields,omitempty" yaml:"min_fields,omitempty"`
+
+	// StructuredErrors adds sibling attributes for error fields: <key>.type with
+	// the error's Go type, and <key>.code if the error or one it wraps has a
+	// Code() string method. Applies to OTEL and stdout logs. Defaults to false
+	// (error message only).
+	StructuredErrors bool `json:"structured_errors,omitempty" yaml:"structured_errors,omitempty"`
 }
 
 // ContextSchema defines context values to extract for each signal type.
@@ -107,6 +365,240 @@ type ContextSchema struct {
 	Traces []string `json:"traces,omitempty" yaml:"traces,omitempty"`
 }
 
+// Merge returns a schema combining s and other, for config split across files.
+//
+// Metrics and traces are concatenated. Log whitelist/blacklist, exclude_fields,
+// and context key lists are unioned, preserving first-seen order. Boolean settings (stdout,
+// strict_whitelist, pending_span_metric, logs_emitted_metric, best_effort,
+// keep_unserializable, sort_attributes, mirror_meta_attributes,
+// logs.structured_errors) are enabled if either enables them.
+// For scalar settings (stdout_format, scope_name, unused_config_warmup, max_pending, max_attribute_bytes,
+// logs.body_template, logs.min_fields), a non-zero value in other overrides s.
+// logs.body_templates are combined per signal, with other's template winning.
+//
+// The result is not validated; duplicate metric names across the inputs are
+// reported by [Schema.Validate]. [MergeSchemas] rejects conflicts instead.
+func (s Schema) Merge(other Schema) Schema {
+	merged := Schema{
+		Metrics:              append(slices.Clone(s.Metrics), other.Metrics...),
+		Traces:               append(slices.Clone(s.Traces), other.Traces...),
+		ExcludeFields:        unionNames(s.ExcludeFields, other.ExcludeFields),
+		Stdout:               s.Stdout || other.Stdout,
+		StrictWhitelist:      s.StrictWhitelist || other.StrictWhitelist,
+		PendingSpanMetric:    s.PendingSpanMetric || other.PendingSpanMetric,
+		LogsEmittedMetric:    s.LogsEmittedMetric || other.LogsEmittedMetric,
+		BestEffort:           s.BestEffort || other.BestEffort,
+		KeepUnserializable:   s.KeepUnserializable || other.KeepUnserializable,
+		SortAttributes:       s.SortAttributes || other.SortAttributes,
+		MirrorMetaAttributes: s.MirrorMetaAttributes || other.MirrorMetaAttributes,
+		StdoutFormat:         s.StdoutFormat,
+		ScopeName:            s.ScopeName,
+		UnusedConfigWarmup:   s.UnusedConfigWarmup,
+		MaxPending:           s.MaxPending,
+		MaxAttributeBytes:    s.MaxAttributeBytes,
+	}
+	if other.StdoutFormat != "" {
+		merged.StdoutFormat = other.StdoutFormat
+	}
+	if other.ScopeName != "" {
+		merged.ScopeName = other.ScopeName
+	}
+	if other.UnusedConfigWarmup != "" {
+		merged.UnusedConfigWarmup = other.UnusedConfigWarmup
+	}
+	if other.MaxPending != 0 {
+		merged.MaxPending = other.MaxPending
+	}
+	if other.MaxAttributeBytes != 0 {
+		merged.MaxAttributeBytes = other.MaxAttributeBytes
+	}
+
+	if s.Logs != nil || other.Logs != nil {
+		a, b := derefOrZero(s.Logs), derefOrZero(other.Logs)
+		merged.Logs = &LogSchema{
+			Whitelist:    unionNames(a.Whitelist, b.Whitelist),
+			Blacklist:    unionNames(a.Blacklist, b.Blacklist),
+			BodyTemplate: a.BodyTemplate,
+		}
+		if b.BodyTemplate != "" {
+			merged.Logs.BodyTemplate = b.BodyTemplate
+		}
+		if len(a.BodyTemplates) > 0 || len(b.BodyTemplates) > 0 {
+			merged.Logs.BodyTemplates = maps.Clone(a.BodyTemplates)
+			if merged.Logs.BodyTemplates == nil {
+				merged.Logs.BodyTemplates = make(map[string]string, len(b.BodyTemplates))
+			}
+			maps.Copy(merged.Logs.BodyTemplates, b.BodyTemplates)
+		}
+		merged.Logs.MinFields = a.MinFields
+		if b.MinFields != 0 {
+			merged.Logs.MinFields = b.MinFields
+		}
+		merged.Logs.StructuredErrors = a.StructuredErrors || b.StructuredErrors
+	}
+
+	if s.Context != nil || other.Context != nil {
+		a, b := derefOrZero(s.Context), derefOrZero(other.Context)
+		merged.Context = &ContextSchema{
+			Logs:    unionNames(a.Logs, b.Logs),
+			Metrics: unionNames(a.Metrics, b.Metrics),
+			Traces:  unionNames(a.Traces, b.Traces),
+		}
+	}
+
+	return merged
+}
+
+// ExpandEnv returns a copy of s with environment variables substituted in every
+// string value, including list entries and map keys and values. References use
+// [os.Expand] syntax, ${VAR} or $VAR, and unset variables expand to the empty
+// string. $$ produces a literal $.
+//
+// Call it after loading and before Apply, so environment-specific values (e.g.
+// signal: "${SERVICE_ENV}.order.created") stay out of committed config.
+func (s Schema) ExpandEnv() Schema {
+	var out Schema
+	reflect.ValueOf(&out).Elem().Set(expandEnvValue(reflect.ValueOf(s)))
+	return out
+}
+
+// expandEnvValue deep-copies v, expanding environment variables in strings.
+func expandEnvValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.String:
+		out := reflect.New(v.Type()).Elem()
+		out.SetString(os.Expand(v.String(), expandEnvVar))
+		return out
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(expandEnvValue(v.Elem()))
+		return out
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				out.Field(i).Set(expandEnvValue(v.Field(i)))
+			}
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			out.Index(i).Set(expandEnvValue(v.Index(i)))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(expandEnvValue(iter.Key()), expandEnvValue(iter.Value()))
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// expandEnvVar maps a variable name to its value for os.Expand.
+// os.Expand passes "$" for $$, which is kept as a literal dollar sign.
+func expandEnvVar(name string) string {
+	if name == "$" {
+		return "$"
+	}
+	return os.Getenv(name)
+}
+
+// MergeSchemas combines schemas with [Schema.Merge], failing where Merge
+// would silently pick a winner. Use it for a directory-of-configs layout where
+// each file should own its settings.
+//
+// Metrics, traces, and log and context name lists combine as in Merge. Boolean
+// settings such as stdout are enabled if any schema enables them, since an
+// omitted flag cannot be told apart from false. It returns an error if:
+//   - two schemas set different non-zero values for a scalar setting
+//     (stdout_format, scope_name, unused_config_warmup, max_pending, max_attribute_bytes,
+//     logs.body_template, logs.min_fields)
+//   - two schemas define a metric with the same name
+func MergeSchemas(schemas ...Schema) (Schema, error) {
+	var merged Schema
+	metricOwner := make(map[string]int)
+
+	for i, schema := range schemas {
+		for _, m := range schema.Metrics {
+			if j, ok := metricOwner[m.Name]; ok {
+				return Schema{}, fmt.Errorf("schema %d: metric %q already defined by schema %d", i, m.Name, j)
+			}
+			metricOwner[m.Name] = i
+		}
+
+		a, b := derefOrZero(merged.Logs), derefOrZero(schema.Logs)
+		conflicts := []struct {
+			name        string
+			have, other any
+		}{
+			{"stdout_format", merged.StdoutFormat, schema.StdoutFormat},
+			{"scope_name", merged.ScopeName, schema.ScopeName},
+			{"unused_config_warmup", merged.UnusedConfigWarmup, schema.UnusedConfigWarmup},
+			{"max_pending", merged.MaxPending, schema.MaxPending},
+			{"max_attribute_bytes", merged.MaxAttributeBytes, schema.MaxAttributeBytes},
+			{"logs.body_template", a.BodyTemplate, b.BodyTemplate},
+			{"logs.min_fields", a.MinFields, b.MinFields},
+		}
+		for _, c := range conflicts {
+			if !isZero(c.have) && !isZero(c.other) && c.have != c.other {
+				return Schema{}, fmt.Errorf("schema %d: %s %v conflicts with %v", i, c.name, c.other, c.have)
+			}
+		}
+		for _, signal := range slices.Sorted(maps.Keys(b.BodyTemplates)) {
+			if have, ok := a.BodyTemplates[signal]; ok && have != b.BodyTemplates[signal] {
+				return Schema{}, fmt.Errorf("schema %d: logs.body_templates[%s] %q conflicts with %q", i, signal, b.BodyTemplates[signal], have)
+			}
+		}
+
+		merged = merged.Merge(schema)
+	}
+
+	return merged, nil
+}
+
+// isZero reports whether v is the zero value of its type.
+func isZero(v any) bool {
+	return v == nil || reflect.ValueOf(v).IsZero()
+}
+
+// derefOrZero returns *p, or the zero value if p is nil.
+func derefOrZero[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
+
+// unionNames returns the names in a followed by those in b not already present.
+func unionNames(a, b []string) []string {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+
+	out := make([]string, 0, len(a)+len(b))
+	for _, name := range slices.Concat(a, b) {
+		if !slices.Contains(out, name) {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
 // Validate checks that required fields are present in the schema.
 func (s Schema) Validate() error {
 	for i, m := range s.Metrics {
@@ -120,18 +612,233 @@ func (s Schema) Validate() error {
 		if m.Type != "" && m.Type != "counter" && m.ValueKey == "" {
 			return fmt.Errorf("metrics[%d]: value_key is required for type %q", i, m.Type)
 		}
+		switch m.DurationUnit {
+		case "", "ms", "ns":
+		default:
+			return fmt.Errorf("metrics[%d]: duration_unit must be \"ms\" or \"ns\", got %q", i, m.DurationUnit)
+		}
+		switch m.Aggregation {
+		case "", "last":
+		case "max", "min", "sum":
+			if m.Type != "gauge" {
+				return fmt.Errorf("metrics[%d]: aggregation %q is only supported for type \"gauge\"", i, m.Aggregation)
+			}
+		default:
+			return fmt.Errorf("metrics[%d]: aggregation must be one of \"last\", \"max\", \"min\", \"sum\", got %q", i, m.Aggregation)
+		}
+		switch m.HistogramType {
+		case "":
+		case "explicit", "exponential":
+			if m.Type != "histogram" {
+				return fmt.Errorf("metrics[%d]: histogram_type is only supported for type \"histogram\"", i)
+			}
+		default:
+			return fmt.Errorf("metrics[%d]: histogram_type must be \"explicit\" or \"exponential\", got %q", i, m.HistogramType)
+		}
+		if err := validateBuckets(m); err != nil {
+			return fmt.Errorf("metrics[%d]: %w", i, err)
+		}
+		if m.FilterValue != "" && m.FilterKey == "" {
+			return fmt.Errorf("metrics[%d]: filter_value requires filter_key", i)
+		}
+	}
+
+	if s.UnusedConfigWarmup != "" {
+		d, err := time.ParseDuration(s.UnusedConfigWarmup)
+		if err != nil {
+			return fmt.Errorf("unused_config_warmup: %w", err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("unused_config_warmup must be positive, got %q", s.UnusedConfigWarmup)
+		}
+	}
+
+	switch s.StdoutFormat {
+	case "", "text", "json":
+	default:
+		return fmt.Errorf("stdout_format must be \"text\" or \"json\", got %q", s.StdoutFormat)
+	}
+
+	if s.MaxPending < 0 {
+		return fmt.Errorf("max_pending must not be negative, got %d", s.MaxPending)
+	}
+
+	if s.MaxAttributeBytes < 0 {
+		return fmt.Errorf("max_attribute_bytes must not be negative, got %d", s.MaxAttributeBytes)
+	}
+
+	for i, key := range s.ExcludeFields {
+		if key == "" {
+			return fmt.Errorf("exclude_fields[%d]: field key is required", i)
+		}
+	}
+
+	if s.Logs != nil && s.Logs.MinFields < 0 {
+		return fmt.Errorf("logs.min_fields must not be negative, got %d", s.Logs.MinFields)
+	}
+
+	if s.Logs != nil && s.Logs.BodyTemplate != "" {
+		if _, err := parseBodyTemplate(s.Logs.BodyTemplate); err != nil {
+			return fmt.Errorf("logs.body_template: %w", err)
+		}
+	}
+
+	if s.Logs != nil {
+		for _, signal := range slices.Sorted(maps.Keys(s.Logs.BodyTemplates)) {
+			if _, err := parseBodyTemplate(s.Logs.BodyTemplates[signal]); err != nil {
+				return fmt.Errorf("logs.body_templates[%s]: %w", signal, err)
+			}
+		}
+	}
+
+	if err := validateUniqueMetricNames(s.Metrics); err != nil {
+		return err
 	}
 
 	for i, t := range s.Traces {
 		if t.Start == "" {
 			return fmt.Errorf("traces[%d]: start is required", i)
 		}
-		if t.End == "" {
-			return fmt.Errorf("traces[%d]: end is required", i)
+		if t.End == "" && len(t.Ends) == 0 {
+			return fmt.Errorf("traces[%d]: end or ends is required", i)
+		}
+		if err := validateEndSignals(t); err != nil {
+			return fmt.Errorf("traces[%d]: %w", i, err)
 		}
 		if t.CorrelationKey == "" {
 			return fmt.Errorf("traces[%d]: correlation_key is required", i)
 		}
+		if _, err := parseBodyTemplate(t.SpanNameTemplate); err != nil {
+			return fmt.Errorf("traces[%d]: span_name_template: %w", i, err)
+		}
+		if t.SampleRate < 0 || t.SampleRate > 1 {
+			return fmt.Errorf("traces[%d]: sample_rate must be between 0 and 1, got %v", i, t.SampleRate)
+		}
+		if t.DurationMetric != "" && !validInstrumentName(t.DurationMetric) {
+			return fmt.Errorf("traces[%d]: duration_metric %q is not a valid instrument name", i, t.DurationMetric)
+		}
+	}
+
+	if err := validateDurationMetricNames(s.Metrics, s.Traces); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// endSignals returns the trace's end signal names: End first, then Ends.
+func (t TraceSchema) endSignals() []string {
+	if t.End == "" {
+		return slices.Clone(t.Ends)
+	}
+	return append([]string{t.End}, t.Ends...)
+}
+
+// validateEndSignals rejects empty or repeated end signal names, and end
+// signals that are also the start signal.
+func validateEndSignals(t TraceSchema) error {
+	seen := make(map[string]bool)
+	for _, name := range t.endSignals() {
+		switch {
+		case name == "":
+			return fmt.Errorf("ends must not contain an empty signal name")
+		case name == t.Start:
+			return fmt.Errorf("end signal %q is also the start signal", name)
+		case seen[name]:
+			return fmt.Errorf("end signal %q is listed more than once", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// validateDurationMetricNames rejects trace duration metrics whose name is
+// already used by a configured metric or another trace.
+func validateDurationMetricNames(metrics []MetricSchema, traces []TraceSchema) error {
+	used := make(map[string]string, len(metrics))
+	for i, m := range metrics {
+		used[m.Name] = fmt.Sprintf("metrics[%d]", i)
+	}
+
+	for i, t := range traces {
+		if t.DurationMetric == "" {
+			continue
+		}
+		if owner, ok := used[t.DurationMetric]; ok {
+			return fmt.Errorf("traces[%d]: duration_metric %q already used by %s", i, t.DurationMetric, owner)
+		}
+		used[t.DurationMetric] = fmt.Sprintf("traces[%d]", i)
+	}
+
+	return nil
+}
+
+// validInstrumentName reports whether name follows the OTEL instrument name
+// syntax: a letter, then up to 254 letters, digits, '_', '.', '-', or '/'.
+func validInstrumentName(name string) bool {
+	if name == "" || len(name) > 255 {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && (r >= '0' && r <= '9' || r == '_' || r == '.' || r == '-' || r == '/'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// validateBuckets checks a metric's explicit buckets and bucket preset.
+func validateBuckets(m MetricSchema) error {
+	if len(m.Buckets) == 0 && m.BucketsPreset == "" {
+		return nil
+	}
+	if m.Type != "histogram" {
+		return fmt.Errorf("buckets are only supported for type \"histogram\"")
+	}
+	if m.HistogramType == "exponential" {
+		return fmt.Errorf("buckets are not supported for histogram_type \"exponential\"")
+	}
+	if m.BucketsPreset != "" {
+		if _, ok := bucketPresets[m.BucketsPreset]; !ok {
+			return fmt.Errorf("unknown buckets_preset %q", m.BucketsPreset)
+		}
+	}
+	for i, b := range m.Buckets {
+		if math.IsNaN(b) || math.IsInf(b, 0) {
+			return fmt.Errorf("buckets[%d] must be finite, got %v", i, b)
+		}
+		if i > 0 && b <= m.Buckets[i-1] {
+			return fmt.Errorf("buckets must be strictly increasing, got %v after %v", b, m.Buckets[i-1])
+		}
+	}
+	return nil
+}
+
+// validateUniqueMetricNames rejects metrics that share an instrument name.
+// Two instruments with the same name conflict in the OTEL SDK and aggregate
+// unpredictably at the collector.
+func validateUniqueMetricNames(metrics []MetricSchema) error {
+	signalsByName := make(map[string][]string)
+	var names []string
+	for _, m := range metrics {
+		if _, seen := signalsByName[m.Name]; !seen {
+			names = append(names, m.Name)
+		}
+		signalsByName[m.Name] = append(signalsByName[m.Name], m.Signal)
+	}
+
+	var duplicates []string
+	for _, name := range names {
+		if signals := signalsByName[name]; len(signals) > 1 {
+			duplicates = append(duplicates, fmt.Sprintf("%q (signals: %s)", name, strings.Join(signals, ", ")))
+		}
+	}
+
+	if len(duplicates) > 0 {
+		return fmt.Errorf("metrics: duplicate metric names: %s", strings.Join(duplicates, "; "))
 	}
 
 	return nil
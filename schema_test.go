@@ -1,6 +1,7 @@
 package aperture
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -14,6 +15,8 @@ metrics:
     name: test.duration
     type: histogram
     value_key: duration
+    description: Test duration
+    unit: ms
 
 traces:
   - start: TestStart
@@ -55,6 +58,9 @@ stdout: true
 	if schema.Metrics[1].ValueKey != "duration" {
 		t.Errorf("expected value_key duration, got %s", schema.Metrics[1].ValueKey)
 	}
+	if schema.Metrics[1].Description != "Test duration" || schema.Metrics[1].Unit != "ms" {
+		t.Errorf("expected description and unit, got %q, %q", schema.Metrics[1].Description, schema.Metrics[1].Unit)
+	}
 
 	// Validate trace values
 	if schema.Traces[0].SpanTimeout != "5m" {
@@ -95,6 +101,74 @@ func TestLoadSchemaFromJSON_Invalid(t *testing.T) {
 	}
 }
 
+func TestLoadSchemaFromYAMLStrict(t *testing.T) {
+	yaml := `
+logs:
+  whitelist:
+    - order.created
+metrics:
+  - signal: order.created
+    name: orders_total
+`
+	schema, err := LoadSchemaFromYAMLStrict([]byte(yaml))
+	if err != nil {
+		t.Fatalf("LoadSchemaFromYAMLStrict failed: %v", err)
+	}
+	if len(schema.Logs.Whitelist) != 1 || len(schema.Metrics) != 1 {
+		t.Errorf("unexpected schema: %+v", schema)
+	}
+
+	if _, err := LoadSchemaFromYAMLStrict(nil); err != nil {
+		t.Errorf("expected empty input to load, got %v", err)
+	}
+}
+
+func TestLoadSchemaFromYAMLStrict_UnknownKey(t *testing.T) {
+	yaml := `
+logs:
+  wihtelist:
+    - order.created
+`
+	// Lenient loader ignores the typo
+	schema, err := LoadSchemaFromYAML([]byte(yaml))
+	if err != nil {
+		t.Fatalf("LoadSchemaFromYAML failed: %v", err)
+	}
+	if len(schema.Logs.Whitelist) != 0 {
+		t.Errorf("expected empty whitelist, got %v", schema.Logs.Whitelist)
+	}
+
+	_, err = LoadSchemaFromYAMLStrict([]byte(yaml))
+	if err == nil {
+		t.Fatal("expected error for unknown key")
+	}
+	if !strings.Contains(err.Error(), "wihtelist") {
+		t.Errorf("expected error to name the unknown key, got %v", err)
+	}
+}
+
+func TestLoadSchemaFromJSONStrict(t *testing.T) {
+	schema, err := LoadSchemaFromJSONStrict([]byte(`{"stdout": true}`))
+	if err != nil {
+		t.Fatalf("LoadSchemaFromJSONStrict failed: %v", err)
+	}
+	if !schema.Stdout {
+		t.Error("expected stdout to be true")
+	}
+
+	_, err = LoadSchemaFromJSONStrict([]byte(`{"stdotu": true}`))
+	if err == nil {
+		t.Fatal("expected error for unknown key")
+	}
+	if !strings.Contains(err.Error(), "stdotu") {
+		t.Errorf("expected error to name the unknown key, got %v", err)
+	}
+
+	if _, err := LoadSchemaFromJSONStrict([]byte(`{"stdout": true} {}`)); err == nil {
+		t.Error("expected error for trailing data")
+	}
+}
+
 func TestSchemaValidate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -162,6 +236,188 @@ func TestSchemaValidate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "same signal with distinct metric names is valid",
+			schema: Schema{
+				Metrics: []MetricSchema{
+					{Signal: "Test", Name: "test_total"},
+					{Signal: "Test", Name: "test_value", Type: "histogram", ValueKey: "val"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "duplicate metric name",
+			schema: Schema{
+				Metrics: []MetricSchema{
+					{Signal: "A", Name: "test"},
+					{Signal: "B", Name: "test"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duration_unit ns is valid",
+			schema: Schema{
+				Metrics: []MetricSchema{{Signal: "Test", Name: "test", Type: "gauge", ValueKey: "val", DurationUnit: "ns"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown duration_unit",
+			schema: Schema{
+				Metrics: []MetricSchema{{Signal: "Test", Name: "test", Type: "gauge", ValueKey: "val", DurationUnit: "hours"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "gauge aggregation is valid",
+			schema: Schema{
+				Metrics: []MetricSchema{{Signal: "Test", Name: "test", Type: "gauge", ValueKey: "val", Aggregation: "max"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "aggregation on non-gauge",
+			schema: Schema{
+				Metrics: []MetricSchema{{Signal: "Test", Name: "test", Type: "histogram", ValueKey: "val", Aggregation: "sum"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown aggregation",
+			schema: Schema{
+				Metrics: []MetricSchema{{Signal: "Test", Name: "test", Type: "gauge", ValueKey: "val", Aggregation: "avg"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "exponential histogram is valid",
+			schema: Schema{
+				Metrics: []MetricSchema{{Signal: "Test", Name: "test", Type: "histogram", ValueKey: "val", HistogramType: "exponential"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "histogram_type on non-histogram",
+			schema: Schema{
+				Metrics: []MetricSchema{{Signal: "Test", Name: "test", Type: "gauge", ValueKey: "val", HistogramType: "explicit"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown histogram_type",
+			schema: Schema{
+				Metrics: []MetricSchema{{Signal: "Test", Name: "test", Type: "histogram", ValueKey: "val", HistogramType: "linear"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "histogram with buckets preset",
+			schema: Schema{
+				Metrics: []MetricSchema{{Signal: "Test", Name: "test", Type: "histogram", ValueKey: "val", BucketsPreset: "http_latency_ms"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown buckets preset",
+			schema: Schema{
+				Metrics: []MetricSchema{{Signal: "Test", Name: "test", Type: "histogram", ValueKey: "val", BucketsPreset: "latency"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "buckets on non-histogram",
+			schema: Schema{
+				Metrics: []MetricSchema{{Signal: "Test", Name: "test", Type: "gauge", ValueKey: "val", Buckets: []float64{1, 2}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "buckets on exponential histogram",
+			schema: Schema{
+				Metrics: []MetricSchema{{Signal: "Test", Name: "test", Type: "histogram", ValueKey: "val", HistogramType: "exponential", BucketsPreset: "bytes"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "buckets not increasing",
+			schema: Schema{
+				Metrics: []MetricSchema{{Signal: "Test", Name: "test", Type: "histogram", ValueKey: "val", Buckets: []float64{10, 5}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "filter_key with filter_value",
+			schema: Schema{
+				Metrics: []MetricSchema{{Signal: "Test", Name: "test", FilterKey: "status", FilterValue: "refunded"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "filter_value without filter_key",
+			schema: Schema{
+				Metrics: []MetricSchema{{Signal: "Test", Name: "test", FilterValue: "refunded"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid body template",
+			schema: Schema{
+				Logs: &LogSchema{BodyTemplate: "order {order_id} created"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unterminated body template placeholder",
+			schema: Schema{
+				Logs: &LogSchema{BodyTemplate: "order {order_id created"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid per-signal body template",
+			schema: Schema{
+				Logs: &LogSchema{BodyTemplates: map[string]string{"order.created": "order {}"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative min_fields",
+			schema: Schema{
+				Logs: &LogSchema{MinFields: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "negative max_pending",
+			schema:  Schema{MaxPending: -1},
+			wantErr: true,
+		},
+		{
+			name:    "negative max_attribute_bytes",
+			schema:  Schema{MaxAttributeBytes: -1},
+			wantErr: true,
+		},
+		{
+			name: "invalid span_name_template",
+			schema: Schema{
+				Traces: []TraceSchema{{Start: "A", End: "B", CorrelationKey: "id", SpanNameTemplate: "{method"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "span_name with braces is literal",
+			schema: Schema{
+				Traces: []TraceSchema{{Start: "A", End: "B", CorrelationKey: "id", SpanName: "{method"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "empty exclude_fields key",
+			schema:  Schema{ExcludeFields: []string{"password", ""}},
+			wantErr: true,
+		},
 		{
 			name: "valid trace",
 			schema: Schema{
@@ -169,6 +425,38 @@ func TestSchemaValidate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "trace sample_rate out of range",
+			schema: Schema{
+				Traces: []TraceSchema{{Start: "A", End: "B", CorrelationKey: "id", SampleRate: 1.5}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "unused_config_warmup valid",
+			schema:  Schema{UnusedConfigWarmup: "10m"},
+			wantErr: false,
+		},
+		{
+			name:    "unused_config_warmup unparseable",
+			schema:  Schema{UnusedConfigWarmup: "soon"},
+			wantErr: true,
+		},
+		{
+			name:    "unused_config_warmup not positive",
+			schema:  Schema{UnusedConfigWarmup: "0s"},
+			wantErr: true,
+		},
+		{
+			name:    "stdout_format json",
+			schema:  Schema{Stdout: true, StdoutFormat: "json"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid stdout_format",
+			schema:  Schema{Stdout: true, StdoutFormat: "xml"},
+			wantErr: true,
+		},
 		{
 			name: "trace missing start",
 			schema: Schema{
@@ -183,6 +471,41 @@ func TestSchemaValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "trace with ends only",
+			schema: Schema{
+				Traces: []TraceSchema{{Start: "A", Ends: []string{"B", "C"}, CorrelationKey: "id"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "trace with end and ends",
+			schema: Schema{
+				Traces: []TraceSchema{{Start: "A", End: "B", Ends: []string{"C"}, CorrelationKey: "id"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "trace end repeated in ends",
+			schema: Schema{
+				Traces: []TraceSchema{{Start: "A", End: "B", Ends: []string{"B"}, CorrelationKey: "id"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "trace end is the start",
+			schema: Schema{
+				Traces: []TraceSchema{{Start: "A", Ends: []string{"A", "B"}, CorrelationKey: "id"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "trace ends with empty name",
+			schema: Schema{
+				Traces: []TraceSchema{{Start: "A", Ends: []string{"B", ""}, CorrelationKey: "id"}},
+			},
+			wantErr: true,
+		},
 		{
 			name: "trace missing correlation_key",
 			schema: Schema{
@@ -190,6 +513,38 @@ func TestSchemaValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "trace with duration_metric",
+			schema: Schema{
+				Traces: []TraceSchema{{Start: "A", End: "B", CorrelationKey: "id", DurationMetric: "http.server.duration"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "trace with invalid duration_metric",
+			schema: Schema{
+				Traces: []TraceSchema{{Start: "A", End: "B", CorrelationKey: "id", DurationMetric: "1st duration"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "trace duration_metric clashes with metric",
+			schema: Schema{
+				Metrics: []MetricSchema{{Signal: "A", Name: "request_ms"}},
+				Traces:  []TraceSchema{{Start: "A", End: "B", CorrelationKey: "id", DurationMetric: "request_ms"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "trace duration_metric clashes with trace",
+			schema: Schema{
+				Traces: []TraceSchema{
+					{Start: "A", End: "B", CorrelationKey: "id", DurationMetric: "request_ms"},
+					{Start: "C", End: "D", CorrelationKey: "id", DurationMetric: "request_ms"},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -202,6 +557,74 @@ func TestSchemaValidate(t *testing.T) {
 	}
 }
 
+func TestSchemaValidate_DuplicateMetricNames(t *testing.T) {
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{Signal: "order.created", Name: "orders_total"},
+			{Signal: "order.updated", Name: "orders_total"},
+			{Signal: "cpu.usage", Name: "cpu", Type: "gauge", ValueKey: "percent"},
+			{Signal: "order.deleted", Name: "orders_total"},
+		},
+	}
+
+	err := schema.Validate()
+	if err == nil {
+		t.Fatal("expected error for duplicate metric names, got nil")
+	}
+
+	want := `metrics: duplicate metric names: "orders_total" (signals: order.created, order.updated, order.deleted)`
+	if err.Error() != want {
+		t.Errorf("error = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestLoadSchemaFromYAML_MetricAttributes(t *testing.T) {
+	yaml := `
+metrics:
+  - signal: order.created
+    name: orders_total
+    attributes:
+      tier: premium
+      region: eu
+`
+
+	schema, err := LoadSchemaFromYAML([]byte(yaml))
+	if err != nil {
+		t.Fatalf("LoadSchemaFromYAML failed: %v", err)
+	}
+
+	attrs := schema.Metrics[0].Attributes
+	if len(attrs) != 2 {
+		t.Fatalf("expected 2 attributes, got %d", len(attrs))
+	}
+	if attrs["tier"] != "premium" {
+		t.Errorf("expected tier premium, got %q", attrs["tier"])
+	}
+	if attrs["region"] != "eu" {
+		t.Errorf("expected region eu, got %q", attrs["region"])
+	}
+}
+
+func TestLoadSchemaFromYAML_AttributeRename(t *testing.T) {
+	yaml := `
+metrics:
+  - signal: order.created
+    name: orders_total
+    attribute_rename:
+      order_status: status
+`
+
+	schema, err := LoadSchemaFromYAML([]byte(yaml))
+	if err != nil {
+		t.Fatalf("LoadSchemaFromYAML failed: %v", err)
+	}
+
+	rename := schema.Metrics[0].AttributeRename
+	if len(rename) != 1 || rename["order_status"] != "status" {
+		t.Errorf("expected order_status → status, got %v", rename)
+	}
+}
+
 func TestLoadSchemaFromYAML_Context(t *testing.T) {
 	yaml := `
 context:
@@ -232,3 +655,265 @@ context:
 		t.Errorf("expected 1 trace context key, got %d", len(schema.Context.Traces))
 	}
 }
+
+func TestSchemaMerge(t *testing.T) {
+	metrics := Schema{
+		Metrics: []MetricSchema{
+			{Signal: "order.created", Name: "orders_total"},
+		},
+		Logs:          &LogSchema{Whitelist: []string{"order.created"}},
+		Context:       &ContextSchema{Metrics: []string{"tenant_id"}},
+		ExcludeFields: []string{"password"},
+		StdoutFormat:  "text",
+	}
+	traces := Schema{
+		Traces: []TraceSchema{
+			{Start: "request.started", End: "request.completed", CorrelationKey: "request_id"},
+		},
+		Logs:          &LogSchema{Whitelist: []string{"order.created", "request.completed"}, Blacklist: []string{"debug.tick"}},
+		Context:       &ContextSchema{Metrics: []string{"tenant_id"}, Traces: []string{"user_id"}},
+		ExcludeFields: []string{"password", "token"},
+		Stdout:        true,
+		StdoutFormat:  "json",
+	}
+
+	merged := metrics.Merge(traces)
+
+	if len(merged.Metrics) != 1 || merged.Metrics[0].Name != "orders_total" {
+		t.Errorf("Metrics = %v, want [orders_total]", merged.Metrics)
+	}
+	if len(merged.Traces) != 1 || merged.Traces[0].Start != "request.started" {
+		t.Errorf("Traces = %v, want [request.started]", merged.Traces)
+	}
+	if !merged.Stdout {
+		t.Error("expected Stdout to be enabled")
+	}
+	if merged.StdoutFormat != "json" {
+		t.Errorf("StdoutFormat = %q, want %q", merged.StdoutFormat, "json")
+	}
+
+	if got := merged.Logs.Whitelist; len(got) != 2 || got[0] != "order.created" || got[1] != "request.completed" {
+		t.Errorf("Logs.Whitelist = %v, want [order.created request.completed]", got)
+	}
+	if got := merged.Logs.Blacklist; len(got) != 1 || got[0] != "debug.tick" {
+		t.Errorf("Logs.Blacklist = %v, want [debug.tick]", got)
+	}
+	if got := merged.ExcludeFields; len(got) != 2 || got[0] != "password" || got[1] != "token" {
+		t.Errorf("ExcludeFields = %v, want [password token]", got)
+	}
+	if got := merged.Context.Metrics; len(got) != 1 || got[0] != "tenant_id" {
+		t.Errorf("Context.Metrics = %v, want [tenant_id]", got)
+	}
+	if got := merged.Context.Traces; len(got) != 1 || got[0] != "user_id" {
+		t.Errorf("Context.Traces = %v, want [user_id]", got)
+	}
+	if merged.Context.Logs != nil {
+		t.Errorf("Context.Logs = %v, want nil", merged.Context.Logs)
+	}
+
+	// Inputs are not modified
+	if len(metrics.Traces) != 0 || len(metrics.Logs.Whitelist) != 1 {
+		t.Error("Merge modified its receiver")
+	}
+}
+
+func TestSchemaMerge_Empty(t *testing.T) {
+	merged := Schema{}.Merge(Schema{})
+	if merged.Logs != nil || merged.Context != nil {
+		t.Errorf("expected nil Logs and Context, got %+v", merged)
+	}
+	if merged.StdoutFormat != "" || merged.UnusedConfigWarmup != "" {
+		t.Errorf("expected empty scalar settings, got %+v", merged)
+	}
+
+	// An empty value does not override
+	merged = Schema{UnusedConfigWarmup: "10m"}.Merge(Schema{})
+	if merged.UnusedConfigWarmup != "10m" {
+		t.Errorf("UnusedConfigWarmup = %q, want %q", merged.UnusedConfigWarmup, "10m")
+	}
+}
+
+func TestSchemaMerge_DuplicateMetricNames(t *testing.T) {
+	a := Schema{Metrics: []MetricSchema{{Signal: "order.created", Name: "orders_total"}}}
+	b := Schema{Metrics: []MetricSchema{{Signal: "order.updated", Name: "orders_total"}}}
+
+	if err := a.Merge(b).Validate(); err == nil {
+		t.Fatal("expected error for duplicate metric names across merged schemas, got nil")
+	}
+}
+
+func TestMergeSchemas(t *testing.T) {
+	metrics := Schema{
+		Metrics: []MetricSchema{{Signal: "order.created", Name: "orders_total"}},
+		Logs:    &LogSchema{Whitelist: []string{"order.created"}},
+	}
+	traces := Schema{
+		Traces:       []TraceSchema{{Start: "job.started", End: "job.done", CorrelationKey: "job_id"}},
+		Logs:         &LogSchema{Whitelist: []string{"job.started"}},
+		Stdout:       true,
+		StdoutFormat: "json",
+	}
+	logs := Schema{StdoutFormat: "json"} // same value is not a conflict
+
+	merged, err := MergeSchemas(metrics, traces, logs)
+	if err != nil {
+		t.Fatalf("MergeSchemas failed: %v", err)
+	}
+	if len(merged.Metrics) != 1 || len(merged.Traces) != 1 {
+		t.Errorf("expected 1 metric and 1 trace, got %d and %d", len(merged.Metrics), len(merged.Traces))
+	}
+	if len(merged.Logs.Whitelist) != 2 {
+		t.Errorf("expected 2 whitelisted signals, got %v", merged.Logs.Whitelist)
+	}
+	if !merged.Stdout || merged.StdoutFormat != "json" {
+		t.Errorf("expected stdout json, got stdout=%v format=%q", merged.Stdout, merged.StdoutFormat)
+	}
+
+	if merged, err := MergeSchemas(); err != nil || merged.Logs != nil || merged.Metrics != nil {
+		t.Errorf("expected empty schema for no input, got %+v, %v", merged, err)
+	}
+}
+
+func TestMergeSchemas_Conflicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		schemas []Schema
+		wantErr string
+	}{
+		{
+			name: "duplicate metric name",
+			schemas: []Schema{
+				{Metrics: []MetricSchema{{Signal: "order.created", Name: "orders_total"}}},
+				{Metrics: []MetricSchema{{Signal: "order.updated", Name: "orders_total"}}},
+			},
+			wantErr: `metric "orders_total" already defined by schema 0`,
+		},
+		{
+			name:    "stdout_format",
+			schemas: []Schema{{StdoutFormat: "text"}, {StdoutFormat: "json"}},
+			wantErr: "stdout_format",
+		},
+		{
+			name:    "max_pending",
+			schemas: []Schema{{MaxPending: 10}, {}, {MaxPending: 20}},
+			wantErr: "schema 2: max_pending",
+		},
+		{
+			name:    "scope_name",
+			schemas: []Schema{{ScopeName: "billing"}, {ScopeName: "orders"}},
+			wantErr: "schema 1: scope_name",
+		},
+		{
+			name:    "max_attribute_bytes",
+			schemas: []Schema{{MaxAttributeBytes: 1024}, {MaxAttributeBytes: 2048}},
+			wantErr: "schema 1: max_attribute_bytes",
+		},
+		{
+			name: "logs.min_fields",
+			schemas: []Schema{
+				{Logs: &LogSchema{MinFields: 1}},
+				{Logs: &LogSchema{MinFields: 2}},
+			},
+			wantErr: "logs.min_fields",
+		},
+		{
+			name: "logs.body_templates",
+			schemas: []Schema{
+				{Logs: &LogSchema{BodyTemplates: map[string]string{"order.created": "order {order_id}"}}},
+				{Logs: &LogSchema{BodyTemplates: map[string]string{"order.created": "created {order_id}"}}},
+			},
+			wantErr: "logs.body_templates[order.created]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := MergeSchemas(tt.schemas...)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadSchemaFromReader(t *testing.T) {
+	schema, err := LoadSchemaFromReader(strings.NewReader("stdout: true\n"), "yaml")
+	if err != nil {
+		t.Fatalf("LoadSchemaFromReader(yaml) failed: %v", err)
+	}
+	if !schema.Stdout {
+		t.Error("expected stdout to be true from YAML")
+	}
+
+	schema, err = LoadSchemaFromReader(strings.NewReader(`{"stdout": true}`), "JSON")
+	if err != nil {
+		t.Fatalf("LoadSchemaFromReader(json) failed: %v", err)
+	}
+	if !schema.Stdout {
+		t.Error("expected stdout to be true from JSON")
+	}
+
+	if _, err := LoadSchemaFromReader(strings.NewReader(""), "toml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestSchemaExpandEnv(t *testing.T) {
+	t.Setenv("SERVICE_ENV", "prod")
+	t.Setenv("REGION", "eu-west-1")
+
+	schema := Schema{
+		Metrics: []MetricSchema{
+			{
+				Signal:     "${SERVICE_ENV}.order.created",
+				Name:       "orders_total",
+				Attributes: map[string]string{"region": "$REGION", "${SERVICE_ENV}_tier": "gold"},
+			},
+		},
+		Logs: &LogSchema{
+			Whitelist:    []string{"${SERVICE_ENV}.order.created"},
+			BodyTemplate: "cost $$5 in ${UNSET_VAR_FOR_TEST}{region}",
+		},
+		Stdout: true,
+	}
+
+	expanded := schema.ExpandEnv()
+
+	m := expanded.Metrics[0]
+	if m.Signal != "prod.order.created" {
+		t.Errorf("Signal = %q, want prod.order.created", m.Signal)
+	}
+	if m.Attributes["region"] != "eu-west-1" {
+		t.Errorf("Attributes[region] = %q, want eu-west-1", m.Attributes["region"])
+	}
+	if m.Attributes["prod_tier"] != "gold" {
+		t.Errorf("expected map key to be expanded, got %v", m.Attributes)
+	}
+	if expanded.Logs.Whitelist[0] != "prod.order.created" {
+		t.Errorf("Whitelist[0] = %q, want prod.order.created", expanded.Logs.Whitelist[0])
+	}
+	if expanded.Logs.BodyTemplate != "cost $5 in {region}" {
+		t.Errorf("BodyTemplate = %q, want %q", expanded.Logs.BodyTemplate, "cost $5 in {region}")
+	}
+	if !expanded.Stdout {
+		t.Error("expected non-string fields to be preserved")
+	}
+
+	// The original is not modified
+	if schema.Metrics[0].Signal != "${SERVICE_ENV}.order.created" || schema.Logs.Whitelist[0] != "${SERVICE_ENV}.order.created" {
+		t.Error("ExpandEnv modified the original schema")
+	}
+	if _, ok := schema.Metrics[0].Attributes["${SERVICE_ENV}_tier"]; !ok {
+		t.Error("ExpandEnv modified the original attributes map")
+	}
+}
+
+func TestSchemaExpandEnv_Empty(t *testing.T) {
+	expanded := Schema{}.ExpandEnv()
+	if expanded.Logs != nil || expanded.Metrics != nil {
+		t.Errorf("expected empty schema, got %+v", expanded)
+	}
+}
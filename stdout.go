@@ -9,17 +9,30 @@ import (
 	"github.com/zoobzio/capitan"
 )
 
-// stdoutLogger writes human-readable logs to stdout using slog.
+// stdoutLogger writes logs to stdout using slog, as text or JSON lines.
 type stdoutLogger struct {
-	logger *slog.Logger
+	logger           *slog.Logger
+	structuredErrors bool                // add type and code attributes for error fields
+	excludeFields    map[string]struct{} // field keys left out of the output
 }
 
-// newStdoutLogger creates a new stdout logger.
-func newStdoutLogger() *stdoutLogger {
+// newStdoutLogger creates a new stdout logger in the given format.
+func newStdoutLogger(format StdoutFormat, structuredErrors bool, excludeFields map[string]struct{}) *stdoutLogger {
+	opts := &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	}
+
+	var handler slog.Handler
+	if format == StdoutFormatJSON {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	}
+
 	return &stdoutLogger{
-		logger: slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-			Level: slog.LevelDebug,
-		})),
+		logger:           slog.New(handler),
+		structuredErrors: structuredErrors,
+		excludeFields:    excludeFields,
 	}
 }
 
@@ -47,8 +60,11 @@ func (sl *stdoutLogger) logEvent(ctx context.Context, e *capitan.Event, contextK
 	}
 
 	// Add all event fields
-	for _, field := range e.Fields() {
+	for _, field := range withoutFields(e.Fields(), sl.excludeFields) {
 		attrs = append(attrs, fieldToSlogAttr(field))
+		if sl.structuredErrors {
+			attrs = append(attrs, errorDetailSlogAttrs(field)...)
+		}
 	}
 
 	// Extract and add context values if configured
@@ -131,10 +147,41 @@ func fieldToSlogAttr(field capitan.Field) slog.Attr {
 		}
 	}
 
+	// Custom types implementing slog.LogValuer render as their resolved value,
+	// including groups
+	type valueGetter interface {
+		Value() any
+	}
+	if vg, ok := field.(valueGetter); ok {
+		if lv, ok := vg.Value().(slog.LogValuer); ok {
+			return slog.Attr{Key: key, Value: slog.AnyValue(lv).Resolve()}
+		}
+	}
+
 	// Fallback for unknown types
 	return slog.String(key, "unsupported")
 }
 
+// errorDetailSlogAttrs returns the <key>.type and <key>.code slog attributes
+// for an error field, or nil for other fields.
+func errorDetailSlogAttrs(field capitan.Field) []slog.Attr {
+	gf, ok := field.(capitan.GenericField[error])
+	if !ok || field.Variant() != capitan.VariantError {
+		return nil
+	}
+
+	key := field.Key().Name()
+	typeName, code, hasCode := errorDetails(gf.Get())
+	if typeName == "" {
+		return nil
+	}
+	attrs := []slog.Attr{slog.String(key+".type", typeName)}
+	if hasCode {
+		attrs = append(attrs, slog.String(key+".code", code))
+	}
+	return attrs
+}
+
 // contextValueToSlogAttr converts a context value to a slog attribute.
 func contextValueToSlogAttr(name string, val any) slog.Attr {
 	switch v := val.(type) {
@@ -3,6 +3,7 @@ package aperture
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"log/slog"
@@ -13,6 +14,8 @@ import (
 
 	apertesting "github.com/zoobzio/aperture/testing"
 	"github.com/zoobzio/capitan"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestStdoutLogging(t *testing.T) {
@@ -299,6 +302,45 @@ func TestStdoutLoggingWithContextExtraction(t *testing.T) {
 	}
 }
 
+// maskedToken is a test type that logs a redacted form of itself.
+type maskedToken string
+
+func (m maskedToken) LogValue() slog.Value {
+	return slog.StringValue(string(m[:2]) + "***")
+}
+
+// customer is a test type that logs as a group.
+type customer struct {
+	ID   string
+	Tier int
+}
+
+func (c customer) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("id", c.ID), slog.Int("tier", c.Tier))
+}
+
+func TestFieldToSlogAttr_LogValuer(t *testing.T) {
+	attr := fieldToSlogAttr(capitan.NewKey[maskedToken]("token", "test.MaskedToken").Field("sk-12345"))
+	if attr.Key != "token" || attr.Value.Kind() != slog.KindString || attr.Value.String() != "sk***" {
+		t.Errorf("token = %s, want sk***", attr)
+	}
+
+	attr = fieldToSlogAttr(capitan.NewKey[customer]("customer", "test.Customer").Field(customer{ID: "c-1", Tier: 2}))
+	if attr.Value.Kind() != slog.KindGroup {
+		t.Fatalf("customer kind = %s, want Group", attr.Value.Kind())
+	}
+	group := attr.Value.Group()
+	if len(group) != 2 || group[0].Value.String() != "c-1" || group[1].Value.Int64() != 2 {
+		t.Errorf("customer group = %v, want [id=c-1 tier=2]", group)
+	}
+
+	// Custom types without LogValue keep the fallback
+	attr = fieldToSlogAttr(capitan.NewKey[struct{ N int }]("plain", "test.Plain").Field(struct{ N int }{1}))
+	if attr.Value.String() != "unsupported" {
+		t.Errorf("plain = %s, want unsupported", attr.Value)
+	}
+}
+
 func TestFieldToSlogAttr_AllVariants(t *testing.T) {
 	now := time.Now()
 	dur := 100 * time.Millisecond
@@ -606,3 +648,170 @@ func TestContextValueToSlogAttr_AllTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestStdoutLoggingJSONFormat(t *testing.T) {
+	ctx := context.Background()
+
+	// Define context key
+	type ctxKey string
+	requestIDKey := ctxKey("request_id")
+	ctx = context.WithValue(ctx, requestIDKey, "REQ-12345")
+
+	// Capture stdout
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	// Create capitan and signal
+	c := capitan.New()
+	testSignal := capitan.NewSignal("test.signal", "Test signal description")
+	testKey := capitan.NewStringKey("test_key")
+
+	pvs, err := apertesting.TestProviders(ctx, "test-service", "v1.0.0", "localhost:4318")
+	if err != nil {
+		t.Fatalf("Failed to create providers: %v", err)
+	}
+
+	sh, err := New(c, pvs.Log, pvs.Meter, pvs.Trace)
+	if err != nil {
+		t.Fatalf("Failed to create aperture: %v", err)
+	}
+	defer sh.Close()
+
+	sh.RegisterContextKey("request_id", requestIDKey)
+
+	schema := Schema{
+		Stdout:       true,
+		StdoutFormat: "json",
+		Context: &ContextSchema{
+			Logs: []string{"request_id"},
+		},
+	}
+
+	err = sh.Apply(schema)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	// Emit event
+	c.Emit(ctx, testSignal, testKey.Field("test_value"))
+
+	// Give time for async processing
+	time.Sleep(100 * time.Millisecond)
+
+	// Restore stdout and read captured output
+	w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	// Each line must be a standalone JSON object
+	var entry map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("Expected JSON line, got %q: %v", line, err)
+		}
+		if m["signal"] == "test.signal" {
+			entry = m
+		}
+	}
+	if entry == nil {
+		t.Fatalf("Expected JSON entry for test.signal, got: %s", buf.String())
+	}
+
+	if entry["msg"] != "Test signal description" {
+		t.Errorf("msg = %v, want signal description", entry["msg"])
+	}
+	if entry["level"] != "INFO" {
+		t.Errorf("level = %v, want INFO", entry["level"])
+	}
+	if entry["test_key"] != "test_value" {
+		t.Errorf("test_key = %v, want test_value", entry["test_key"])
+	}
+	if entry["request_id"] != "REQ-12345" {
+		t.Errorf("request_id = %v, want REQ-12345", entry["request_id"])
+	}
+}
+
+func TestErrorDetailSlogAttrs(t *testing.T) {
+	field := capitan.NewErrorKey("err").Field(&codedError{code: "card_declined"})
+
+	attrs := errorDetailSlogAttrs(field)
+	if len(attrs) != 2 {
+		t.Fatalf("expected 2 attributes, got %d", len(attrs))
+	}
+	if attrs[0].Key != "err.type" || attrs[0].Value.String() != "*aperture.codedError" {
+		t.Errorf("attrs[0] = %s=%s, want err.type=*aperture.codedError", attrs[0].Key, attrs[0].Value)
+	}
+	if attrs[1].Key != "err.code" || attrs[1].Value.String() != "card_declined" {
+		t.Errorf("attrs[1] = %s=%s, want err.code=card_declined", attrs[1].Key, attrs[1].Value)
+	}
+
+	if attrs := errorDetailSlogAttrs(capitan.NewStringKey("msg").Field("hi")); attrs != nil {
+		t.Errorf("expected nil for non-error field, got %v", attrs)
+	}
+}
+
+func TestParseStdoutFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected StdoutFormat
+	}{
+		{"", StdoutFormatText},
+		{"text", StdoutFormatText},
+		{"json", StdoutFormatJSON},
+	}
+
+	for _, tt := range tests {
+		if got := parseStdoutFormat(tt.input); got != tt.expected {
+			t.Errorf("parseStdoutFormat(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestStdoutLoggingExcludeFields(t *testing.T) {
+	ctx := context.Background()
+
+	// Capture stdout
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	c := capitan.New()
+	testSignal := capitan.NewSignal("user.login", "User login")
+	userKey := capitan.NewStringKey("user_id")
+	passwordKey := capitan.NewStringKey("password")
+
+	sh, err := New(c, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("Failed to create aperture: %v", err)
+	}
+	defer sh.Close()
+
+	schema := Schema{
+		Stdout:        true,
+		StdoutFormat:  "json",
+		ExcludeFields: []string{"password"},
+	}
+	if err := sh.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	c.Emit(ctx, testSignal, userKey.Field("user-1"), passwordKey.Field("hunter2"))
+	sh.Flush(ctx)
+
+	// Restore stdout and read captured output
+	w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	output := buf.String()
+	if !strings.Contains(output, `"user_id":"user-1"`) {
+		t.Errorf("Expected user_id in output, got: %s", output)
+	}
+	if strings.Contains(output, "hunter2") {
+		t.Errorf("Excluded password field written to stdout: %s", output)
+	}
+}
@@ -3,19 +3,26 @@
 package testing
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/zoobzio/capitan"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/log/embedded"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
@@ -58,6 +65,59 @@ func (p *Providers) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// ProviderConfig configures the OTLP providers built by [TestProvidersWithConfig].
+// Zero values for the optional fields keep the OTEL SDK defaults.
+type ProviderConfig struct {
+	// Retry configures retries of failed exports. Nil keeps the SDK default:
+	// enabled, 5s initial interval, 30s max interval, 1m max elapsed time.
+	Retry *RetryConfig
+
+	// ServiceName is the service.name resource attribute (required).
+	ServiceName string
+
+	// ServiceVersion is the service.version resource attribute (required).
+	ServiceVersion string
+
+	// Endpoint is the OTLP collector address, e.g. "localhost:4318" for HTTP
+	// or "localhost:4317" for gRPC (required).
+	Endpoint string
+
+	// Transport is the OTLP transport: "http" (default) or "grpc".
+	Transport string
+
+	// Compression is the export payload compression: "none" (default) or "gzip".
+	Compression string
+
+	// ResourceAttributes are added to the resource shared by all three
+	// providers, e.g. deployment.environment or host.name. ServiceName and
+	// ServiceVersion take precedence over attributes with the same key.
+	ResourceAttributes []attribute.KeyValue
+
+	// ExportTimeout bounds each export request, including retries.
+	// Zero keeps the SDK default of 10s.
+	ExportTimeout time.Duration
+
+	// MetricInterval is how often metrics are collected and exported.
+	// Zero keeps the default of 60s. Call Providers.Meter.ForceFlush to
+	// export immediately instead.
+	MetricInterval time.Duration
+}
+
+// RetryConfig configures exponential backoff retries of failed exports.
+type RetryConfig struct {
+	// InitialInterval is the wait before the first retry.
+	InitialInterval time.Duration
+
+	// MaxInterval caps the wait between retries.
+	MaxInterval time.Duration
+
+	// MaxElapsedTime is the total time spent retrying before the batch is dropped.
+	MaxElapsedTime time.Duration
+
+	// Enabled turns retries on. When false, a failed export is dropped immediately.
+	Enabled bool
+}
+
 // TestProviders creates OTLP providers configured for testing.
 // Uses insecure connections suitable for local OTLP collectors.
 //
@@ -75,31 +135,60 @@ func (p *Providers) Shutdown(ctx context.Context) error {
 //	}
 //	defer ap.Close()
 func TestProviders(ctx context.Context, serviceName, serviceVersion, otlpEndpoint string) (*Providers, error) {
-	if serviceName == "" {
+	return TestProvidersWithConfig(ctx, ProviderConfig{
+		ServiceName:    serviceName,
+		ServiceVersion: serviceVersion,
+		Endpoint:       otlpEndpoint,
+	})
+}
+
+// TestProvidersWithConfig creates OTLP providers like [TestProviders], with
+// transport, compression, export timeout, and retry settings applied to all
+// three exporters, a custom metric export interval, and extra resource
+// attributes applied to all three providers.
+//
+// Example:
+//
+//	pvs, err := testing.TestProvidersWithConfig(ctx, testing.ProviderConfig{
+//	    ServiceName:    "test-service",
+//	    ServiceVersion: "v1.0.0",
+//	    Endpoint:       "localhost:4318",
+//	    ExportTimeout:  2 * time.Second,
+//	    Retry:          &testing.RetryConfig{Enabled: false},
+//	    ResourceAttributes: []attribute.KeyValue{
+//	        attribute.String("deployment.environment", "test"),
+//	    },
+//	})
+func TestProvidersWithConfig(ctx context.Context, cfg ProviderConfig) (*Providers, error) {
+	if cfg.ServiceName == "" {
 		return nil, fmt.Errorf("service name is required")
 	}
-	if serviceVersion == "" {
+	if cfg.ServiceVersion == "" {
 		return nil, fmt.Errorf("service version is required")
 	}
-	if otlpEndpoint == "" {
+	if cfg.Endpoint == "" {
 		return nil, fmt.Errorf("OTLP endpoint is required")
 	}
+	switch cfg.Compression {
+	case "", "none", "gzip":
+	default:
+		return nil, fmt.Errorf("compression must be \"none\" or \"gzip\", got %q", cfg.Compression)
+	}
+	if cfg.MetricInterval < 0 {
+		return nil, fmt.Errorf("metric interval must not be negative, got %v", cfg.MetricInterval)
+	}
+	switch cfg.Transport {
+	case "", "http", "grpc":
+	default:
+		return nil, fmt.Errorf("transport must be \"http\" or \"grpc\", got %q", cfg.Transport)
+	}
 
-	res, err := resource.Merge(
-		resource.Default(),
-		resource.NewSchemaless(
-			semconv.ServiceName(serviceName),
-			semconv.ServiceVersion(serviceVersion),
-		),
-	)
+	res, err := providerResource(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("creating resource: %w", err)
 	}
 
-	logExporter, err := otlploghttp.New(ctx,
-		otlploghttp.WithEndpoint(otlpEndpoint),
-		otlploghttp.WithInsecure(),
-	)
+	logExporter, err := newLogExporter(ctx, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("creating log exporter: %w", err)
 	}
@@ -109,10 +198,7 @@ func TestProviders(ctx context.Context, serviceName, serviceVersion, otlpEndpoin
 		sdklog.WithProcessor(sdklog.NewBatchProcessor(logExporter)),
 	)
 
-	metricExporter, err := otlpmetrichttp.New(ctx,
-		otlpmetrichttp.WithEndpoint(otlpEndpoint),
-		otlpmetrichttp.WithInsecure(),
-	)
+	metricExporter, err := newMetricExporter(ctx, cfg)
 	if err != nil {
 		_ = logProvider.Shutdown(ctx) //nolint:errcheck // best-effort cleanup
 		return nil, fmt.Errorf("creating metric exporter: %w", err)
@@ -121,14 +207,11 @@ func TestProviders(ctx context.Context, serviceName, serviceVersion, otlpEndpoin
 	meterProvider := sdkmetric.NewMeterProvider(
 		sdkmetric.WithResource(res),
 		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter,
-			sdkmetric.WithInterval(60*time.Second),
+			sdkmetric.WithInterval(cmp.Or(cfg.MetricInterval, 60*time.Second)),
 		)),
 	)
 
-	traceExporter, err := otlptracehttp.New(ctx,
-		otlptracehttp.WithEndpoint(otlpEndpoint),
-		otlptracehttp.WithInsecure(),
-	)
+	traceExporter, err := newTraceExporter(ctx, cfg)
 	if err != nil {
 		_ = logProvider.Shutdown(ctx)   //nolint:errcheck // best-effort cleanup
 		_ = meterProvider.Shutdown(ctx) //nolint:errcheck // best-effort cleanup
@@ -148,6 +231,149 @@ func TestProviders(ctx context.Context, serviceName, serviceVersion, otlpEndpoin
 	}, nil
 }
 
+// providerResource builds the resource shared by the providers. Service name
+// and version are added last, so they win over a caller attribute with the
+// same key.
+func providerResource(cfg ProviderConfig) (*resource.Resource, error) {
+	attrs := append(slices.Clone(cfg.ResourceAttributes),
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(cfg.ServiceVersion),
+	)
+	return resource.Merge(resource.Default(), resource.NewSchemaless(attrs...))
+}
+
+// newLogExporter creates the OTLP log exporter for cfg.Transport.
+func newLogExporter(ctx context.Context, cfg ProviderConfig) (sdklog.Exporter, error) {
+	if cfg.Transport == "grpc" {
+		return otlploggrpc.New(ctx, grpcLogExporterOptions(cfg)...)
+	}
+	return otlploghttp.New(ctx, logExporterOptions(cfg)...)
+}
+
+// newMetricExporter creates the OTLP metric exporter for cfg.Transport.
+func newMetricExporter(ctx context.Context, cfg ProviderConfig) (sdkmetric.Exporter, error) {
+	if cfg.Transport == "grpc" {
+		return otlpmetricgrpc.New(ctx, grpcMetricExporterOptions(cfg)...)
+	}
+	return otlpmetrichttp.New(ctx, metricExporterOptions(cfg)...)
+}
+
+// newTraceExporter creates the OTLP trace exporter for cfg.Transport.
+func newTraceExporter(ctx context.Context, cfg ProviderConfig) (sdktrace.SpanExporter, error) {
+	if cfg.Transport == "grpc" {
+		return otlptracegrpc.New(ctx, grpcTraceExporterOptions(cfg)...)
+	}
+	return otlptracehttp.New(ctx, traceExporterOptions(cfg)...)
+}
+
+// otlpRetryConfig is the layout shared by the RetryConfig types of the OTLP
+// exporter packages, so one mapping from RetryConfig serves all six.
+type otlpRetryConfig interface {
+	~struct {
+		Enabled         bool
+		InitialInterval time.Duration
+		MaxInterval     time.Duration
+		MaxElapsedTime  time.Duration
+	}
+}
+
+// exporterOptions holds one OTLP exporter package's option constructors.
+// Each package has its own option and retry types; build maps cfg onto them.
+type exporterOptions[O any, R otlpRetryConfig] struct {
+	endpoint func(string) O
+	insecure O
+	gzip     O
+	timeout  func(time.Duration) O
+	retry    func(R) O
+}
+
+// build returns the exporter options for cfg: endpoint and insecure always,
+// then compression, timeout, and retry when set.
+func (b exporterOptions[O, R]) build(cfg ProviderConfig) []O {
+	opts := []O{b.endpoint(cfg.Endpoint), b.insecure}
+	if cfg.Compression == "gzip" {
+		opts = append(opts, b.gzip)
+	}
+	if cfg.ExportTimeout > 0 {
+		opts = append(opts, b.timeout(cfg.ExportTimeout))
+	}
+	if cfg.Retry != nil {
+		opts = append(opts, b.retry(R{
+			Enabled:         cfg.Retry.Enabled,
+			InitialInterval: cfg.Retry.InitialInterval,
+			MaxInterval:     cfg.Retry.MaxInterval,
+			MaxElapsedTime:  cfg.Retry.MaxElapsedTime,
+		}))
+	}
+	return opts
+}
+
+// logExporterOptions builds OTLP HTTP log exporter options from cfg.
+func logExporterOptions(cfg ProviderConfig) []otlploghttp.Option {
+	return exporterOptions[otlploghttp.Option, otlploghttp.RetryConfig]{
+		endpoint: otlploghttp.WithEndpoint,
+		insecure: otlploghttp.WithInsecure(),
+		gzip:     otlploghttp.WithCompression(otlploghttp.GzipCompression),
+		timeout:  otlploghttp.WithTimeout,
+		retry:    otlploghttp.WithRetry,
+	}.build(cfg)
+}
+
+// metricExporterOptions builds OTLP HTTP metric exporter options from cfg.
+func metricExporterOptions(cfg ProviderConfig) []otlpmetrichttp.Option {
+	return exporterOptions[otlpmetrichttp.Option, otlpmetrichttp.RetryConfig]{
+		endpoint: otlpmetrichttp.WithEndpoint,
+		insecure: otlpmetrichttp.WithInsecure(),
+		gzip:     otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression),
+		timeout:  otlpmetrichttp.WithTimeout,
+		retry:    otlpmetrichttp.WithRetry,
+	}.build(cfg)
+}
+
+// traceExporterOptions builds OTLP HTTP trace exporter options from cfg.
+func traceExporterOptions(cfg ProviderConfig) []otlptracehttp.Option {
+	return exporterOptions[otlptracehttp.Option, otlptracehttp.RetryConfig]{
+		endpoint: otlptracehttp.WithEndpoint,
+		insecure: otlptracehttp.WithInsecure(),
+		gzip:     otlptracehttp.WithCompression(otlptracehttp.GzipCompression),
+		timeout:  otlptracehttp.WithTimeout,
+		retry:    otlptracehttp.WithRetry,
+	}.build(cfg)
+}
+
+// grpcLogExporterOptions builds OTLP gRPC log exporter options from cfg.
+func grpcLogExporterOptions(cfg ProviderConfig) []otlploggrpc.Option {
+	return exporterOptions[otlploggrpc.Option, otlploggrpc.RetryConfig]{
+		endpoint: otlploggrpc.WithEndpoint,
+		insecure: otlploggrpc.WithInsecure(),
+		gzip:     otlploggrpc.WithCompressor("gzip"),
+		timeout:  otlploggrpc.WithTimeout,
+		retry:    otlploggrpc.WithRetry,
+	}.build(cfg)
+}
+
+// grpcMetricExporterOptions builds OTLP gRPC metric exporter options from cfg.
+func grpcMetricExporterOptions(cfg ProviderConfig) []otlpmetricgrpc.Option {
+	return exporterOptions[otlpmetricgrpc.Option, otlpmetricgrpc.RetryConfig]{
+		endpoint: otlpmetricgrpc.WithEndpoint,
+		insecure: otlpmetricgrpc.WithInsecure(),
+		gzip:     otlpmetricgrpc.WithCompressor("gzip"),
+		timeout:  otlpmetricgrpc.WithTimeout,
+		retry:    otlpmetricgrpc.WithRetry,
+	}.build(cfg)
+}
+
+// grpcTraceExporterOptions builds OTLP gRPC trace exporter options from cfg.
+func grpcTraceExporterOptions(cfg ProviderConfig) []otlptracegrpc.Option {
+	return exporterOptions[otlptracegrpc.Option, otlptracegrpc.RetryConfig]{
+		endpoint: otlptracegrpc.WithEndpoint,
+		insecure: otlptracegrpc.WithInsecure(),
+		gzip:     otlptracegrpc.WithCompressor("gzip"),
+		timeout:  otlptracegrpc.WithTimeout,
+		retry:    otlptracegrpc.WithRetry,
+	}.build(cfg)
+}
+
 // LogCapture captures OTEL log records for testing and verification.
 // Thread-safe for concurrent log capture.
 type LogCapture struct {
@@ -200,6 +426,93 @@ func (lc *LogCapture) WaitForCount(n int, timeout time.Duration) bool {
 	return false
 }
 
+// WaitForSignal blocks until a record with the given aperture.signal
+// attribute is captured or timeout occurs. Returns a copy of the first
+// matching record, or nil on timeout.
+func (lc *LogCapture) WaitForSignal(name string, timeout time.Duration) *log.Record {
+	deadline := time.Now().Add(timeout)
+	for {
+		if record := findSignalRecord(lc.Records(), name); record != nil {
+			return record
+		}
+		if !time.Now().Before(deadline) {
+			return nil
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// findSignalRecord returns the first record whose aperture.signal attribute
+// equals name, or nil if none does.
+func findSignalRecord(records []log.Record, name string) *log.Record {
+	for i := range records {
+		var found bool
+		records[i].WalkAttributes(func(kv log.KeyValue) bool {
+			if kv.Key == "aperture.signal" && kv.Value.AsString() == name {
+				found = true
+				return false
+			}
+			return true
+		})
+		if found {
+			return &records[i]
+		}
+	}
+	return nil
+}
+
+// LogRecordAttributes returns a record's attributes keyed by name.
+//
+// Values are converted to string, int64, float64, bool, or []byte; slices
+// become []any and maps become map[string]any. If a key repeats, the last
+// value wins.
+func LogRecordAttributes(r log.Record) map[string]any {
+	attrs := make(map[string]any, r.AttributesLen())
+	r.WalkAttributes(func(kv log.KeyValue) bool {
+		attrs[kv.Key] = logValueToAny(kv.Value)
+		return true
+	})
+	return attrs
+}
+
+// AttributeValue returns the value of a record attribute, converted as in
+// LogRecordAttributes, and whether the attribute is present.
+func AttributeValue(r log.Record, key string) (any, bool) {
+	v, ok := LogRecordAttributes(r)[key]
+	return v, ok
+}
+
+// logValueToAny converts a log value to its Go equivalent.
+func logValueToAny(v log.Value) any {
+	switch v.Kind() {
+	case log.KindString:
+		return v.AsString()
+	case log.KindInt64:
+		return v.AsInt64()
+	case log.KindFloat64:
+		return v.AsFloat64()
+	case log.KindBool:
+		return v.AsBool()
+	case log.KindBytes:
+		return v.AsBytes()
+	case log.KindSlice:
+		items := v.AsSlice()
+		out := make([]any, len(items))
+		for i, item := range items {
+			out[i] = logValueToAny(item)
+		}
+		return out
+	case log.KindMap:
+		out := make(map[string]any)
+		for _, kv := range v.AsMap() {
+			out[kv.Key] = logValueToAny(kv.Value)
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
 // MockLogger is a mock OTEL logger that captures records for testing.
 type MockLogger struct {
 	embedded.Logger
@@ -328,3 +641,265 @@ func (ec *EventCapture) WaitForCount(n int, timeout time.Duration) bool {
 	}
 	return false
 }
+
+// SpanStub is a snapshot of a finished span.
+type SpanStub struct {
+	Name       string
+	StartTime  time.Time
+	EndTime    time.Time
+	Attributes []attribute.KeyValue
+	Status     sdktrace.Status
+}
+
+// Duration returns the time between span start and end.
+func (s SpanStub) Duration() time.Duration {
+	return s.EndTime.Sub(s.StartTime)
+}
+
+// SpanCapture captures finished spans for testing and verification.
+// Thread-safe for concurrent span capture.
+type SpanCapture struct {
+	spans []SpanStub
+	mu    sync.Mutex
+}
+
+// NewSpanCapture creates a new SpanCapture instance.
+func NewSpanCapture() *SpanCapture {
+	return &SpanCapture{
+		spans: make([]SpanStub, 0),
+	}
+}
+
+// OnStart is a no-op; spans are captured when they end.
+func (*SpanCapture) OnStart(_ context.Context, _ sdktrace.ReadWriteSpan) {}
+
+// OnEnd captures the finished span.
+func (sc *SpanCapture) OnEnd(s sdktrace.ReadOnlySpan) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.spans = append(sc.spans, SpanStub{
+		Name:       s.Name(),
+		StartTime:  s.StartTime(),
+		EndTime:    s.EndTime(),
+		Attributes: s.Attributes(),
+		Status:     s.Status(),
+	})
+}
+
+// Shutdown is a no-op.
+func (*SpanCapture) Shutdown(_ context.Context) error {
+	return nil
+}
+
+// ForceFlush is a no-op; spans are captured synchronously.
+func (*SpanCapture) ForceFlush(_ context.Context) error {
+	return nil
+}
+
+// Spans returns a copy of all captured spans.
+func (sc *SpanCapture) Spans() []SpanStub {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	result := make([]SpanStub, len(sc.spans))
+	copy(result, sc.spans)
+	return result
+}
+
+// Count returns the number of captured spans.
+func (sc *SpanCapture) Count() int {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return len(sc.spans)
+}
+
+// Reset clears all captured spans.
+func (sc *SpanCapture) Reset() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.spans = sc.spans[:0]
+}
+
+// WaitForCount blocks until the capture has at least n spans or timeout occurs.
+// Returns true if count reached, false if timeout.
+func (sc *SpanCapture) WaitForCount(n int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if sc.Count() >= n {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return false
+}
+
+// InMemoryTracerProvider is an SDK tracer provider that captures finished
+// spans in memory instead of exporting them.
+type InMemoryTracerProvider struct {
+	*sdktrace.TracerProvider
+	capture *SpanCapture
+}
+
+// NewInMemoryTracerProvider creates a tracer provider that records every
+// span into a SpanCapture.
+func NewInMemoryTracerProvider() *InMemoryTracerProvider {
+	capture := NewSpanCapture()
+	return &InMemoryTracerProvider{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(capture)),
+		capture:        capture,
+	}
+}
+
+// Capture returns the underlying SpanCapture for assertions.
+func (p *InMemoryTracerProvider) Capture() *SpanCapture {
+	return p.capture
+}
+
+// MetricStub is a flattened snapshot of one collected metric.
+type MetricStub struct {
+	Name        string
+	Description string
+	Unit        string
+	Type        string // "counter", "updowncounter", "gauge", "histogram", or "exponential_histogram"
+	DataPoints  []DataPointStub
+}
+
+// DataPointStub is a flattened metric data point. Int64 values are converted
+// to float64. Count, Sum, Bounds and BucketCounts are set for histograms only.
+type DataPointStub struct {
+	Attributes   map[string]string
+	Value        float64
+	Count        uint64
+	Sum          float64
+	Bounds       []float64
+	BucketCounts []uint64
+}
+
+// Total returns the sum of Value across all data points, or of Sum for histograms.
+func (m MetricStub) Total() float64 {
+	var total float64
+	for _, dp := range m.DataPoints {
+		total += dp.Value + dp.Sum
+	}
+	return total
+}
+
+// ManualMetricReader collects metrics on demand for testing and verification.
+type ManualMetricReader struct {
+	reader *sdkmetric.ManualReader
+}
+
+// NewManualMetricReader creates a new ManualMetricReader.
+// Register it with sdkmetric.WithReader(r.Reader()).
+func NewManualMetricReader() *ManualMetricReader {
+	return &ManualMetricReader{
+		reader: sdkmetric.NewManualReader(),
+	}
+}
+
+// Reader returns the underlying SDK reader for registration on a meter provider.
+func (r *ManualMetricReader) Reader() sdkmetric.Reader {
+	return r.reader
+}
+
+// Collect gathers all metrics recorded so far, flattened into stubs.
+// Returns nil if the reader is not registered or has been shut down.
+func (r *ManualMetricReader) Collect(ctx context.Context) []MetricStub {
+	var rm metricdata.ResourceMetrics
+	if err := r.reader.Collect(ctx, &rm); err != nil {
+		return nil
+	}
+
+	var stubs []MetricStub
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			stub := MetricStub{
+				Name:        m.Name,
+				Description: m.Description,
+				Unit:        m.Unit,
+			}
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				stub.Type = sumType(data.IsMonotonic)
+				for _, dp := range data.DataPoints {
+					stub.DataPoints = append(stub.DataPoints, DataPointStub{Attributes: attributeMap(dp.Attributes), Value: float64(dp.Value)})
+				}
+			case metricdata.Sum[float64]:
+				stub.Type = sumType(data.IsMonotonic)
+				for _, dp := range data.DataPoints {
+					stub.DataPoints = append(stub.DataPoints, DataPointStub{Attributes: attributeMap(dp.Attributes), Value: dp.Value})
+				}
+			case metricdata.Gauge[int64]:
+				stub.Type = "gauge"
+				for _, dp := range data.DataPoints {
+					stub.DataPoints = append(stub.DataPoints, DataPointStub{Attributes: attributeMap(dp.Attributes), Value: float64(dp.Value)})
+				}
+			case metricdata.Gauge[float64]:
+				stub.Type = "gauge"
+				for _, dp := range data.DataPoints {
+					stub.DataPoints = append(stub.DataPoints, DataPointStub{Attributes: attributeMap(dp.Attributes), Value: dp.Value})
+				}
+			case metricdata.Histogram[int64]:
+				stub.Type = "histogram"
+				for _, dp := range data.DataPoints {
+					stub.DataPoints = append(stub.DataPoints, DataPointStub{
+						Attributes:   attributeMap(dp.Attributes),
+						Count:        dp.Count,
+						Sum:          float64(dp.Sum),
+						Bounds:       dp.Bounds,
+						BucketCounts: dp.BucketCounts,
+					})
+				}
+			case metricdata.Histogram[float64]:
+				stub.Type = "histogram"
+				for _, dp := range data.DataPoints {
+					stub.DataPoints = append(stub.DataPoints, DataPointStub{
+						Attributes:   attributeMap(dp.Attributes),
+						Count:        dp.Count,
+						Sum:          dp.Sum,
+						Bounds:       dp.Bounds,
+						BucketCounts: dp.BucketCounts,
+					})
+				}
+			case metricdata.ExponentialHistogram[int64]:
+				stub.Type = "exponential_histogram"
+				for _, dp := range data.DataPoints {
+					stub.DataPoints = append(stub.DataPoints, DataPointStub{Attributes: attributeMap(dp.Attributes), Count: dp.Count, Sum: float64(dp.Sum)})
+				}
+			case metricdata.ExponentialHistogram[float64]:
+				stub.Type = "exponential_histogram"
+				for _, dp := range data.DataPoints {
+					stub.DataPoints = append(stub.DataPoints, DataPointStub{Attributes: attributeMap(dp.Attributes), Count: dp.Count, Sum: dp.Sum})
+				}
+			}
+			stubs = append(stubs, stub)
+		}
+	}
+	return stubs
+}
+
+// Metric collects metrics and returns the one with the given name.
+func (r *ManualMetricReader) Metric(ctx context.Context, name string) (MetricStub, bool) {
+	for _, m := range r.Collect(ctx) {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return MetricStub{}, false
+}
+
+// sumType maps a sum's monotonicity to its instrument type.
+func sumType(monotonic bool) string {
+	if monotonic {
+		return "counter"
+	}
+	return "updowncounter"
+}
+
+// attributeMap flattens an attribute set into emitted string values.
+func attributeMap(set attribute.Set) map[string]string {
+	attrs := make(map[string]string, set.Len())
+	for _, kv := range set.ToSlice() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	return attrs
+}
@@ -2,11 +2,18 @@ package testing
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/zoobzio/capitan"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 )
 
 func TestTestProviders(t *testing.T) {
@@ -91,6 +98,220 @@ func TestTestProviders(t *testing.T) {
 	}
 }
 
+func TestTestProvidersWithConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ProviderConfig
+	}{
+		{
+			name: "sdk defaults",
+			cfg:  ProviderConfig{},
+		},
+		{
+			name: "timeout and retry",
+			cfg: ProviderConfig{
+				ExportTimeout: 2 * time.Second,
+				Retry: &RetryConfig{
+					Enabled:         true,
+					InitialInterval: 100 * time.Millisecond,
+					MaxInterval:     time.Second,
+					MaxElapsedTime:  5 * time.Second,
+				},
+			},
+		},
+		{
+			name: "retry disabled",
+			cfg:  ProviderConfig{Retry: &RetryConfig{Enabled: false}},
+		},
+		{
+			name: "no compression",
+			cfg:  ProviderConfig{Compression: "none"},
+		},
+		{
+			name: "gzip compression",
+			cfg:  ProviderConfig{Compression: "gzip"},
+		},
+		{
+			name: "grpc transport",
+			cfg:  ProviderConfig{Transport: "grpc", Compression: "gzip"},
+		},
+		{
+			name: "resource attributes",
+			cfg: ProviderConfig{ResourceAttributes: []attribute.KeyValue{
+				attribute.String("deployment.environment", "test"),
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			cfg := tt.cfg
+			cfg.ServiceName = "test-service"
+			cfg.ServiceVersion = "v1.0.0"
+			cfg.Endpoint = "localhost:4318"
+
+			pvs, err := TestProvidersWithConfig(ctx, cfg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer pvs.Shutdown(ctx)
+
+			if pvs.Log == nil || pvs.Meter == nil || pvs.Trace == nil {
+				t.Errorf("expected all providers, got %+v", pvs)
+			}
+		})
+	}
+}
+
+func TestProviderResource(t *testing.T) {
+	res, err := providerResource(ProviderConfig{
+		ServiceName:    "test-service",
+		ServiceVersion: "v1.0.0",
+		ResourceAttributes: []attribute.KeyValue{
+			attribute.String("deployment.environment", "staging"),
+			attribute.String("host.name", "runner-1"),
+			attribute.String("service.name", "ignored"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[attribute.Key]string{
+		"deployment.environment": "staging",
+		"host.name":              "runner-1",
+		"service.name":           "test-service",
+		"service.version":        "v1.0.0",
+	}
+	set := res.Set()
+	for key, value := range want {
+		got, ok := set.Value(key)
+		if !ok || got.AsString() != value {
+			t.Errorf("resource %s = %q, want %q", key, got.AsString(), value)
+		}
+	}
+}
+
+func TestExporterOptions(t *testing.T) {
+	base := ProviderConfig{Endpoint: "localhost:4318"}
+	if got := len(logExporterOptions(base)); got != 2 {
+		t.Errorf("expected 2 log exporter options by default, got %d", got)
+	}
+
+	base.ExportTimeout = time.Second
+	base.Retry = &RetryConfig{Enabled: true}
+	if got := len(logExporterOptions(base)); got != 4 {
+		t.Errorf("expected 4 log exporter options, got %d", got)
+	}
+	if got := len(metricExporterOptions(base)); got != 4 {
+		t.Errorf("expected 4 metric exporter options, got %d", got)
+	}
+	if got := len(traceExporterOptions(base)); got != 4 {
+		t.Errorf("expected 4 trace exporter options, got %d", got)
+	}
+
+	base.Compression = "gzip"
+	if got := len(logExporterOptions(base)); got != 5 {
+		t.Errorf("expected 5 log exporter options with gzip, got %d", got)
+	}
+	if got := len(metricExporterOptions(base)); got != 5 {
+		t.Errorf("expected 5 metric exporter options with gzip, got %d", got)
+	}
+	if got := len(traceExporterOptions(base)); got != 5 {
+		t.Errorf("expected 5 trace exporter options with gzip, got %d", got)
+	}
+
+	if got := len(grpcLogExporterOptions(base)); got != 5 {
+		t.Errorf("expected 5 gRPC log exporter options with gzip, got %d", got)
+	}
+	if got := len(grpcMetricExporterOptions(base)); got != 5 {
+		t.Errorf("expected 5 gRPC metric exporter options with gzip, got %d", got)
+	}
+	if got := len(grpcTraceExporterOptions(base)); got != 5 {
+		t.Errorf("expected 5 gRPC trace exporter options with gzip, got %d", got)
+	}
+	if got := len(grpcLogExporterOptions(ProviderConfig{Endpoint: "localhost:4317"})); got != 2 {
+		t.Errorf("expected 2 gRPC log exporter options by default, got %d", got)
+	}
+}
+
+func TestTestProvidersWithConfig_InvalidCompression(t *testing.T) {
+	_, err := TestProvidersWithConfig(context.Background(), ProviderConfig{
+		ServiceName:    "test-service",
+		ServiceVersion: "v1.0.0",
+		Endpoint:       "localhost:4318",
+		Compression:    "zstd",
+	})
+	if err == nil {
+		t.Fatal("expected error for unsupported compression")
+	}
+}
+
+func TestTestProvidersWithConfig_MetricInterval(t *testing.T) {
+	exports := make(chan struct{}, 10)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/metrics" {
+			select {
+			case exports <- struct{}{}:
+			default:
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pvs, err := TestProvidersWithConfig(ctx, ProviderConfig{
+		ServiceName:    "test-service",
+		ServiceVersion: "v1.0.0",
+		Endpoint:       strings.TrimPrefix(collector.URL, "http://"),
+		MetricInterval: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer pvs.Shutdown(ctx)
+
+	counter, err := pvs.Meter.Meter("test").Int64Counter("orders")
+	if err != nil {
+		t.Fatalf("creating counter: %v", err)
+	}
+	counter.Add(ctx, 1)
+
+	select {
+	case <-exports:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a metric export within the interval")
+	}
+
+	_, err = TestProvidersWithConfig(ctx, ProviderConfig{
+		ServiceName:    "test-service",
+		ServiceVersion: "v1.0.0",
+		Endpoint:       "localhost:4318",
+		MetricInterval: -time.Second,
+	})
+	if err == nil {
+		t.Error("expected error for negative metric interval")
+	}
+}
+
+func TestTestProvidersWithConfig_InvalidTransport(t *testing.T) {
+	_, err := TestProvidersWithConfig(context.Background(), ProviderConfig{
+		ServiceName:    "test-service",
+		ServiceVersion: "v1.0.0",
+		Endpoint:       "localhost:4318",
+		Transport:      "thrift",
+	})
+	if err == nil {
+		t.Fatal("expected error for unsupported transport")
+	}
+}
+
 func TestLogCapture(t *testing.T) {
 	t.Run("basic operations", func(t *testing.T) {
 		capture := NewLogCapture()
@@ -264,3 +485,206 @@ func TestEventCapture(t *testing.T) {
 		}
 	})
 }
+
+func TestInMemoryTracerProvider(t *testing.T) {
+	t.Run("captures ended spans", func(t *testing.T) {
+		provider := NewInMemoryTracerProvider()
+		defer provider.Shutdown(context.Background())
+
+		_, span := provider.Tracer("test").Start(context.Background(), "op")
+		span.SetAttributes(attribute.String("key", "value"))
+		span.SetStatus(codes.Error, "failed")
+		span.End()
+
+		capture := provider.Capture()
+		if !capture.WaitForCount(1, time.Second) {
+			t.Fatal("timeout waiting for span")
+		}
+
+		spans := capture.Spans()
+		if spans[0].Name != "op" {
+			t.Errorf("expected name op, got %s", spans[0].Name)
+		}
+		if spans[0].Duration() < 0 {
+			t.Errorf("expected non-negative duration, got %v", spans[0].Duration())
+		}
+		if len(spans[0].Attributes) != 1 || spans[0].Attributes[0].Value.AsString() != "value" {
+			t.Errorf("unexpected attributes: %v", spans[0].Attributes)
+		}
+		if spans[0].Status.Code != codes.Error {
+			t.Errorf("expected error status, got %v", spans[0].Status.Code)
+		}
+	})
+
+	t.Run("unended spans not captured", func(t *testing.T) {
+		provider := NewInMemoryTracerProvider()
+		defer provider.Shutdown(context.Background())
+
+		provider.Tracer("test").Start(context.Background(), "open")
+
+		if provider.Capture().Count() != 0 {
+			t.Errorf("expected 0 spans, got %d", provider.Capture().Count())
+		}
+	})
+
+	t.Run("reset clears spans", func(t *testing.T) {
+		capture := NewSpanCapture()
+		capture.Reset()
+
+		if capture.Count() != 0 {
+			t.Errorf("expected 0 after reset, got %d", capture.Count())
+		}
+		if capture.WaitForCount(1, 10*time.Millisecond) {
+			t.Error("expected timeout, got success")
+		}
+	})
+}
+
+func TestManualMetricReader(t *testing.T) {
+	t.Run("flattens instruments", func(t *testing.T) {
+		ctx := context.Background()
+		reader := NewManualMetricReader()
+		provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader.Reader()))
+		defer provider.Shutdown(ctx)
+
+		meter := provider.Meter("test")
+		counter, _ := meter.Int64Counter("requests", metric.WithUnit("1"))
+		counter.Add(ctx, 2, metric.WithAttributes(attribute.String("route", "/a")))
+		counter.Add(ctx, 1, metric.WithAttributes(attribute.String("route", "/b")))
+		updown, _ := meter.Float64UpDownCounter("queue")
+		updown.Add(ctx, -1.5)
+		hist, _ := meter.Float64Histogram("latency", metric.WithExplicitBucketBoundaries(10, 100))
+		hist.Record(ctx, 5)
+		hist.Record(ctx, 50)
+
+		m, ok := reader.Metric(ctx, "requests")
+		if !ok {
+			t.Fatal("requests not collected")
+		}
+		if m.Type != "counter" || m.Unit != "1" {
+			t.Errorf("unexpected counter stub: %+v", m)
+		}
+		if m.Total() != 3 {
+			t.Errorf("expected total 3, got %v", m.Total())
+		}
+		for _, dp := range m.DataPoints {
+			if dp.Attributes["route"] == "/a" && dp.Value != 2 {
+				t.Errorf("expected /a = 2, got %v", dp.Value)
+			}
+		}
+
+		m, ok = reader.Metric(ctx, "queue")
+		if !ok || m.Type != "updowncounter" || m.Total() != -1.5 {
+			t.Errorf("unexpected updowncounter stub: %+v", m)
+		}
+
+		m, ok = reader.Metric(ctx, "latency")
+		if !ok || m.Type != "histogram" {
+			t.Fatalf("unexpected histogram stub: %+v", m)
+		}
+		dp := m.DataPoints[0]
+		if dp.Count != 2 || dp.Sum != 55 {
+			t.Errorf("expected count 2 sum 55, got %d %v", dp.Count, dp.Sum)
+		}
+		if len(dp.BucketCounts) != 3 || dp.BucketCounts[0] != 1 || dp.BucketCounts[1] != 1 {
+			t.Errorf("unexpected bucket counts %v", dp.BucketCounts)
+		}
+	})
+
+	t.Run("unregistered reader collects nothing", func(t *testing.T) {
+		reader := NewManualMetricReader()
+		if stubs := reader.Collect(context.Background()); stubs != nil {
+			t.Errorf("expected nil, got %v", stubs)
+		}
+		if _, ok := reader.Metric(context.Background(), "missing"); ok {
+			t.Error("expected missing metric")
+		}
+	})
+}
+
+func TestLogCapture_WaitForSignal(t *testing.T) {
+	t.Run("finds matching record", func(t *testing.T) {
+		logger := NewMockLogger()
+
+		var other, diag log.Record
+		other.AddAttributes(log.String("aperture.signal", "order.created"))
+		diag.AddAttributes(log.String("aperture.signal", "aperture:metric:value_missing"), log.String("metric_name", "orders"))
+
+		go func() {
+			logger.Emit(context.Background(), other)
+			time.Sleep(5 * time.Millisecond)
+			logger.Emit(context.Background(), diag)
+		}()
+
+		record := logger.Capture().WaitForSignal("aperture:metric:value_missing", time.Second)
+		if record == nil {
+			t.Fatal("expected matching record")
+		}
+		var name string
+		record.WalkAttributes(func(kv log.KeyValue) bool {
+			if kv.Key == "metric_name" {
+				name = kv.Value.AsString()
+			}
+			return true
+		})
+		if name != "orders" {
+			t.Errorf("expected metric_name orders, got %q", name)
+		}
+	})
+
+	t.Run("nil on timeout", func(t *testing.T) {
+		capture := NewLogCapture()
+		if record := capture.WaitForSignal("missing", 10*time.Millisecond); record != nil {
+			t.Errorf("expected nil, got %v", record)
+		}
+	})
+}
+
+func TestLogRecordAttributes(t *testing.T) {
+	var record log.Record
+	record.AddAttributes(
+		log.String("name", "order"),
+		log.Int64("count", 3),
+		log.Float64("ratio", 0.5),
+		log.Bool("ok", true),
+		log.Bytes("raw", []byte{0x01}),
+		log.Slice("tags", log.StringValue("a"), log.Int64Value(1)),
+		log.Map("meta", log.String("region", "eu")),
+		log.Empty("none"),
+		log.String("name", "override"),
+	)
+
+	attrs := LogRecordAttributes(record)
+
+	if attrs["name"] != "override" {
+		t.Errorf("expected last name to win, got %v", attrs["name"])
+	}
+	if attrs["count"] != int64(3) {
+		t.Errorf("expected count int64(3), got %#v", attrs["count"])
+	}
+	if attrs["ratio"] != 0.5 {
+		t.Errorf("expected ratio 0.5, got %#v", attrs["ratio"])
+	}
+	if attrs["ok"] != true {
+		t.Errorf("expected ok true, got %#v", attrs["ok"])
+	}
+	if raw, _ := attrs["raw"].([]byte); len(raw) != 1 || raw[0] != 0x01 {
+		t.Errorf("expected raw bytes, got %#v", attrs["raw"])
+	}
+	if tags, _ := attrs["tags"].([]any); len(tags) != 2 || tags[0] != "a" || tags[1] != int64(1) {
+		t.Errorf("expected tags slice, got %#v", attrs["tags"])
+	}
+	if meta, _ := attrs["meta"].(map[string]any); meta["region"] != "eu" {
+		t.Errorf("expected meta map, got %#v", attrs["meta"])
+	}
+	if v, ok := attrs["none"]; !ok || v != nil {
+		t.Errorf("expected none present and nil, got %#v, %v", v, ok)
+	}
+
+	if v, ok := AttributeValue(record, "count"); !ok || v != int64(3) {
+		t.Errorf("AttributeValue(count) = %#v, %v", v, ok)
+	}
+	if _, ok := AttributeValue(record, "missing"); ok {
+		t.Error("expected missing attribute to be absent")
+	}
+}
@@ -10,6 +10,8 @@ import (
 	apertesting "github.com/zoobzio/aperture/testing"
 	"github.com/zoobzio/capitan"
 	"go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	tracenoop "go.opentelemetry.io/otel/trace/noop"
 )
 
@@ -281,3 +283,75 @@ func TestConcurrency_HighVolumeLogging(t *testing.T) {
 		t.Errorf("expected %d log records, got %d", expectedTotal, len(records))
 	}
 }
+
+func TestConcurrency_GaugeAggregationAcrossEmitters(t *testing.T) {
+	ctx := context.Background()
+
+	cap := capitan.New()
+	defer cap.Shutdown()
+
+	sig := capitan.NewSignal("concurrent.depth", "Concurrent queue depth")
+	depthKey := capitan.NewInt64Key("depth")
+
+	schema := aperture.Schema{
+		Metrics: []aperture.MetricSchema{
+			{Signal: "concurrent.depth", Name: "depth_max", Type: "gauge", ValueKey: "depth", Aggregation: "max"},
+			{Signal: "concurrent.depth", Name: "depth_min", Type: "gauge", ValueKey: "depth", Aggregation: "min"},
+		},
+	}
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	ap, err := aperture.New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create aperture: %v", err)
+	}
+	defer ap.Close()
+
+	err = ap.Apply(schema)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	// Each goroutine reports a different range; the interleaving is nondeterministic
+	// but the aggregated extremes are not.
+	const goroutines = 10
+	const perGoroutine = 100
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				cap.Emit(ctx, sig, depthKey.Field(int64(g*perGoroutine+i)))
+			}
+		}(g)
+	}
+
+	wg.Wait()
+	time.Sleep(100 * time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	got := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if data, ok := m.Data.(metricdata.Gauge[int64]); ok && len(data.DataPoints) == 1 {
+				got[m.Name] = data.DataPoints[0].Value
+			}
+		}
+	}
+
+	if want := int64(goroutines*perGoroutine - 1); got["depth_max"] != want {
+		t.Errorf("depth_max = %d, want %d", got["depth_max"], want)
+	}
+	if got["depth_min"] != 0 {
+		t.Errorf("depth_min = %d, want 0", got["depth_min"])
+	}
+}
@@ -8,7 +8,10 @@ import (
 	"github.com/zoobzio/aperture"
 	apertesting "github.com/zoobzio/aperture/testing"
 	"github.com/zoobzio/capitan"
+	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/metric/noop"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	tracenoop "go.opentelemetry.io/otel/trace/noop"
 )
 
@@ -177,12 +180,10 @@ func TestScenario_TraceCorrelation(t *testing.T) {
 		},
 	}
 
-	pvs, err := apertesting.TestProviders(ctx, "test-service", "v1.0.0", "localhost:4318")
-	if err != nil {
-		t.Fatalf("failed to create providers: %v", err)
-	}
+	traceProv := apertesting.NewInMemoryTracerProvider()
+	defer traceProv.Shutdown(ctx)
 
-	ap, err := aperture.New(cap, pvs.Log, pvs.Meter, pvs.Trace)
+	ap, err := aperture.New(cap, apertesting.NewMockLoggerProvider(), noop.NewMeterProvider(), traceProv)
 	if err != nil {
 		t.Fatalf("failed to create aperture: %v", err)
 	}
@@ -203,7 +204,18 @@ func TestScenario_TraceCorrelation(t *testing.T) {
 	time.Sleep(5 * time.Millisecond)
 	cap.Emit(ctx, reqCompleted, requestID.Field("REQ-002"))
 
-	time.Sleep(50 * time.Millisecond)
+	capture := traceProv.Capture()
+	if !capture.WaitForCount(2, time.Second) {
+		t.Fatalf("expected 2 spans, got %d", capture.Count())
+	}
+	for _, span := range capture.Spans() {
+		if span.Name != "http_request" {
+			t.Errorf("expected span name http_request, got %s", span.Name)
+		}
+		if span.Duration() < 5*time.Millisecond {
+			t.Errorf("expected duration of at least 5ms, got %v", span.Duration())
+		}
+	}
 }
 
 func TestScenario_TraceOutOfOrder(t *testing.T) {
@@ -251,6 +263,75 @@ func TestScenario_TraceOutOfOrder(t *testing.T) {
 	time.Sleep(50 * time.Millisecond)
 }
 
+func TestScenario_TraceOutOfOrder_EndBeforeStart(t *testing.T) {
+	ctx := context.Background()
+
+	cap := capitan.New()
+	defer cap.Shutdown()
+
+	reqStarted := capitan.NewSignal("request.started", "Request started")
+	reqCompleted := capitan.NewSignal("request.completed", "Request completed")
+	requestID := capitan.NewStringKey("request_id")
+
+	schema := aperture.Schema{
+		Traces: []aperture.TraceSchema{
+			{
+				Start:          "request.started",
+				End:            "request.completed",
+				CorrelationKey: "request_id",
+				SpanName:       "http_request",
+			},
+		},
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	traceProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer traceProvider.Shutdown(ctx)
+
+	mockLog := apertesting.NewMockLoggerProvider()
+	capture := mockLog.Capture()
+
+	ap, err := aperture.New(cap, mockLog, noop.NewMeterProvider(), traceProvider)
+	if err != nil {
+		t.Fatalf("failed to create aperture: %v", err)
+	}
+	defer ap.Close()
+
+	err = ap.Apply(schema)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	// The end is timestamped 10ms before the start, so the raw span would
+	// have a negative duration
+	cap.Emit(ctx, reqCompleted, requestID.Field("REQ-SKEW"))
+	time.Sleep(10 * time.Millisecond)
+	cap.Emit(ctx, reqStarted, requestID.Field("REQ-SKEW"))
+
+	time.Sleep(100 * time.Millisecond)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].EndTime().Before(spans[0].StartTime()) {
+		t.Errorf("span has negative duration: %v", spans[0].EndTime().Sub(spans[0].StartTime()))
+	}
+
+	found := false
+	for _, r := range capture.Records() {
+		r.WalkAttributes(func(kv log.KeyValue) bool {
+			if kv.Key == "aperture.signal" && kv.Value.AsString() == aperture.SignalTraceNegativeDuration.Name() {
+				found = true
+			}
+			return true
+		})
+	}
+	if !found {
+		t.Error("expected aperture:trace:negative_duration diagnostic")
+	}
+}
+
 func TestScenario_LogWhitelist(t *testing.T) {
 	ctx := context.Background()
 
@@ -0,0 +1,96 @@
+// Package prometheus provides aperture test providers that need no collector:
+// metrics are served in the Prometheus text format, and logs and spans are
+// written to stdout.
+//
+// It is a separate module so the Prometheus client and stdout exporters stay
+// out of the module graph of aperture and its testing package.
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	promclient "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	apertesting "github.com/zoobzio/aperture/testing"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
+)
+
+// NewProviders creates providers for local debugging without a collector.
+// Metrics are served in the Prometheus text format by the returned handler,
+// which reads a registry private to these providers; logs and spans are
+// written to stdout as they are emitted.
+//
+// Example:
+//
+//	pvs, handler, err := prometheus.NewProviders("test-service")
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	defer pvs.Shutdown(ctx)
+//
+//	srv := httptest.NewServer(handler) // scrape srv.URL
+//	defer srv.Close()
+func NewProviders(serviceName string) (*apertesting.Providers, http.Handler, error) {
+	if serviceName == "" {
+		return nil, nil, fmt.Errorf("service name is required")
+	}
+
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewSchemaless(semconv.ServiceName(serviceName)),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating resource: %w", err)
+	}
+
+	registry := promclient.NewRegistry()
+	metricExporter, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating prometheus exporter: %w", err)
+	}
+
+	logExporter, err := stdoutlog.New()
+	if err != nil {
+		return nil, nil, errors.Join(
+			fmt.Errorf("creating log exporter: %w", err),
+			metricExporter.Shutdown(context.Background()),
+		)
+	}
+
+	traceExporter, err := stdouttrace.New()
+	if err != nil {
+		return nil, nil, errors.Join(
+			fmt.Errorf("creating trace exporter: %w", err),
+			logExporter.Shutdown(context.Background()),
+			metricExporter.Shutdown(context.Background()),
+		)
+	}
+
+	pvs := &apertesting.Providers{
+		Log: sdklog.NewLoggerProvider(
+			sdklog.WithResource(res),
+			sdklog.WithProcessor(sdklog.NewSimpleProcessor(logExporter)),
+		),
+		Meter: sdkmetric.NewMeterProvider(
+			sdkmetric.WithResource(res),
+			sdkmetric.WithReader(metricExporter),
+		),
+		Trace: sdktrace.NewTracerProvider(
+			sdktrace.WithResource(res),
+			sdktrace.WithSyncer(traceExporter),
+			sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		),
+	}
+
+	return pvs, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}), nil
+}
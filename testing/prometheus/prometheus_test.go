@@ -0,0 +1,54 @@
+package prometheus
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewProviders(t *testing.T) {
+	ctx := context.Background()
+
+	if _, _, err := NewProviders(""); err == nil {
+		t.Error("expected error for missing service name")
+	}
+
+	pvs, handler, err := NewProviders("test-service")
+	if err != nil {
+		t.Fatalf("NewProviders failed: %v", err)
+	}
+	defer pvs.Shutdown(ctx)
+
+	counter, err := pvs.Meter.Meter("test").Int64Counter("orders")
+	if err != nil {
+		t.Fatalf("creating counter: %v", err)
+	}
+	counter.Add(ctx, 3)
+
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("scrape failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading scrape: %v", err)
+	}
+
+	if !strings.Contains(string(body), "orders_total") {
+		t.Errorf("expected orders_total in scrape, got:\n%s", body)
+	}
+
+	// A second set of providers has its own registry
+	pvs2, _, err := NewProviders("test-service")
+	if err != nil {
+		t.Fatalf("second NewProviders failed: %v", err)
+	}
+	defer pvs2.Shutdown(ctx)
+}
@@ -2,68 +2,73 @@ package aperture
 
 import (
 	"context"
+	"encoding"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/zoobzio/capitan"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/trace"
 )
 
-// pendingSpan holds start event data waiting for the corresponding end event.
-type pendingSpan struct {
-	startTime  time.Time       // time.Time (24 bytes)
-	receivedAt time.Time       // For cleanup timeout
-	startCtx   context.Context // interface (16 bytes)
-	spanName      string       // strings (16 bytes each)
-	correlationID string
-}
-
-// pendingEnd holds end event data waiting for the corresponding start event.
-type pendingEnd struct {
-	endTime    time.Time       // time.Time (24 bytes)
-	receivedAt time.Time       // For cleanup timeout
-	endCtx     context.Context // interface (16 bytes)
-	correlationID string       // strings (16 bytes each)
-	spanName      string
-}
+// pendingSpanMetricName is the observable gauge reporting pending starts and ends.
+const pendingSpanMetricName = "aperture_trace_pending_spans"
 
 // tracesHandler manages trace correlation from signal pairs.
 type tracesHandler struct {
 	// Interface first (16 bytes, all pointers)
 	tracer trace.Tracer
 
+	// Interfaces (16 bytes each)
+	pendingStarts PendingStore // starts waiting for their end
+	pendingEnds   PendingStore // ends waiting for their start
+
 	// Pointers and maps (8 bytes each)
-	pendingStarts map[string]*pendingSpan
-	pendingEnds   map[string]*pendingEnd
-	cleanupTicker *time.Ticker
-	stopCleanup   chan struct{}
-	internal      *internalObserver
+	cleanupTicker   *time.Ticker
+	durationMetrics map[string]metric.Float64Histogram // duration_metric name → histogram
+	spanNames       map[string]*bodyTemplate           // span_name_template → compiled template
+	stopCleanup     chan struct{}
+	internal        *internalObserver
+	pendingGauge    metric.Registration // pending_span_metric callback, unregistered on Close
+	now             func() time.Time    // clock for receipt times and expiry, read under mu
 
 	// Slices (pointer in first 8 bytes)
 	config      []traceConfig
 	contextKeys []ContextKey
+	ownedStores []PendingStore // default stores, discarded on Close
 
 	// Non-pointer fields
-	maxTimeout time.Duration
-	mu         sync.Mutex
+	maxTimeout      time.Duration // fallback for pending events stored without a timeout
+	cleanupEvery    time.Duration // cleanup interval, so short timeouts expire promptly
+	maxPending      int           // cap per pending map; zero means no cap
+	sortAttributes  bool          // sort span attributes by key
+	resourceChecked atomic.Bool   // service.name checked on the first recording span
+	mu              sync.Mutex
 }
 
 // newTracesHandler creates a traces handler from config.
-func newTracesHandler(s *Aperture) *tracesHandler {
+func newTracesHandler(s *Aperture) (*tracesHandler, error) {
 	if len(s.config.Traces) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	// Find maximum timeout from all trace configs
+	// Find the timeout range across all trace configs
 	var maxTimeout time.Duration
+	minTimeout := time.Duration(math.MaxInt64)
 	for _, tc := range s.config.Traces {
-		timeout := tc.SpanTimeout
-		if timeout == 0 {
-			timeout = 5 * time.Minute // Default per-config
-		}
-		if timeout > maxTimeout {
-			maxTimeout = timeout
-		}
+		timeout := spanTimeout(tc)
+		maxTimeout = max(maxTimeout, timeout)
+		minTimeout = min(minTimeout, timeout)
 	}
 
 	// Extract context keys if configured
@@ -72,27 +77,119 @@ func newTracesHandler(s *Aperture) *tracesHandler {
 		contextKeys = s.config.ContextExtraction.Traces
 	}
 
+	// Use the configured pending stores, or fresh in-memory ones owned by
+	// this handler
+	var owned []PendingStore
+	pendingStarts, pendingEnds := s.pendingStarts, s.pendingEnds
+	if pendingStarts == nil {
+		pendingStarts = NewMemoryPendingStore()
+		owned = append(owned, pendingStarts)
+	}
+	if pendingEnds == nil {
+		pendingEnds = NewMemoryPendingStore()
+		owned = append(owned, pendingEnds)
+	}
+
 	th := &tracesHandler{
-		tracer:        s.traceProvider.Tracer("capitan"),
-		config:        s.config.Traces,
-		pendingStarts: make(map[string]*pendingSpan),
-		pendingEnds:   make(map[string]*pendingEnd),
-		stopCleanup:   make(chan struct{}),
-		maxTimeout:    maxTimeout,
-		contextKeys:   contextKeys,
-		internal:      s.internalObserver,
+		tracer:          s.traceProvider.Tracer(s.config.scopeName()),
+		config:          s.config.Traces,
+		pendingStarts:   pendingStarts,
+		pendingEnds:     pendingEnds,
+		ownedStores:     owned,
+		durationMetrics: make(map[string]metric.Float64Histogram),
+		spanNames:       make(map[string]*bodyTemplate),
+		stopCleanup:     make(chan struct{}),
+		now:             time.Now,
+		maxTimeout:      maxTimeout,
+		cleanupEvery:    min(max(minTimeout, time.Second), time.Minute),
+		maxPending:      s.config.MaxPending,
+		sortAttributes:  s.config.SortAttributes,
+		contextKeys:     contextKeys,
+		internal:        s.internalObserver,
+	}
+
+	// Compile span name templates (syntax checked by Validate)
+	for _, tc := range s.config.Traces {
+		if tc.SpanNameTemplate == "" {
+			continue
+		}
+		bt, err := parseBodyTemplate(tc.SpanNameTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("span name template %q: %w", tc.SpanNameTemplate, err)
+		}
+		th.spanNames[tc.SpanNameTemplate] = bt
+	}
+
+	// Create duration histograms for trace configs that request one
+	meter := s.meterProvider.Meter(s.config.scopeName())
+	for _, tc := range s.config.Traces {
+		if tc.DurationMetric == "" {
+			continue
+		}
+		hist, err := meter.Float64Histogram(tc.DurationMetric,
+			metric.WithDescription("Duration from "+tc.StartSignalName+" to "+strings.Join(tc.EndSignalNames, " or ")),
+			metric.WithUnit("ms"),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("creating duration metric %q: %w", tc.DurationMetric, err)
+		}
+		th.durationMetrics[tc.DurationMetric] = hist
+	}
+
+	// Register the pending span gauge if enabled
+	if s.config.PendingSpanMetric {
+		if err := th.registerPendingGauge(s.meterProvider.Meter(s.config.internalMeterName())); err != nil {
+			return nil, fmt.Errorf("creating pending span metric: %w", err)
+		}
 	}
 
 	// Start cleanup goroutine
 	th.startCleanup()
 
-	return th
+	return th, nil
+}
+
+// registerPendingGauge reports pending starts and ends as an observable gauge,
+// so a runaway trace config is visible before it exhausts memory.
+func (th *tracesHandler) registerPendingGauge(meter metric.Meter) error {
+	gauge, err := meter.Int64ObservableGauge(
+		pendingSpanMetricName,
+		metric.WithDescription("Trace start and end events waiting for their counterpart"),
+	)
+	if err != nil {
+		return err
+	}
+
+	startAttrs := metric.WithAttributes(attribute.String("kind", "start"))
+	endAttrs := metric.WithAttributes(attribute.String("kind", "end"))
+
+	th.pendingGauge, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		starts, ends := th.pendingCounts()
+		o.ObserveInt64(gauge, int64(starts), startAttrs)
+		o.ObserveInt64(gauge, int64(ends), endAttrs)
+		return nil
+	}, gauge)
+	return err
+}
+
+// pendingCounts returns the number of pending starts and ends.
+// Unsampled and closed-span markers are included, since they also hold memory.
+// Safe on a nil receiver.
+func (th *tracesHandler) pendingCounts() (starts, ends int) {
+	if th == nil {
+		return 0, 0
+	}
+
+	th.mu.Lock()
+	defer th.mu.Unlock()
+
+	return th.pendingStarts.Len(), th.pendingEnds.Len()
 }
 
 // startCleanup begins periodic cleanup of stale spans.
 func (th *tracesHandler) startCleanup() {
-	// Run cleanup every minute
-	th.cleanupTicker = time.NewTicker(1 * time.Minute)
+	// Run cleanup every minute, or sooner for shorter span timeouts
+	th.cleanupTicker = time.NewTicker(th.cleanupEvery)
 
 	go func() {
 		for {
@@ -111,36 +208,76 @@ func (th *tracesHandler) cleanupStaleSpans() {
 	th.mu.Lock()
 	defer th.mu.Unlock()
 
-	now := time.Now()
+	now := th.now()
 
-	// Clean up stale pending starts
-	for id, pending := range th.pendingStarts {
-		age := now.Sub(pending.receivedAt)
-		if age > th.maxTimeout {
-			th.internal.emit(pending.startCtx, SignalTraceExpired,
-				internalCorrelationID.Field(pending.correlationID),
-				internalSpanName.Field(pending.spanName),
-				internalReason.Field("end event not received"),
-			)
-			delete(th.pendingStarts, id)
+	// Clean up stale pending starts; closed-span markers expire silently
+	for id, pending := range th.expired(th.pendingStarts, now) {
+		if pending.Ended {
+			th.pendingStarts.Delete(id)
+			continue
 		}
+		th.internal.emit(pendingContext(pending), SignalTraceExpired,
+			internalCorrelationID.Field(pending.CorrelationID),
+			internalSpanName.Field(pending.SpanName),
+			internalReason.Field("end event not received"),
+		)
+		th.pendingStarts.Delete(id)
 	}
 
 	// Clean up stale pending ends
-	for id, pending := range th.pendingEnds {
-		age := now.Sub(pending.receivedAt)
-		if age > th.maxTimeout {
-			th.internal.emit(pending.endCtx, SignalTraceExpired,
-				internalCorrelationID.Field(pending.correlationID),
-				internalSpanName.Field(pending.spanName),
-				internalReason.Field("start event not received"),
-			)
-			delete(th.pendingEnds, id)
+	for id, pending := range th.expired(th.pendingEnds, now) {
+		th.internal.emit(pendingContext(pending), SignalTraceExpired,
+			internalCorrelationID.Field(pending.CorrelationID),
+			internalSpanName.Field(pending.SpanName),
+			internalReason.Field("start event not received"),
+		)
+		th.pendingEnds.Delete(id)
+	}
+}
+
+// expired returns the events in store that have exceeded their span timeout.
+// They are collected first because a store may not be modified during Range.
+func (th *tracesHandler) expired(store PendingStore, now time.Time) map[string]PendingEvent {
+	stale := make(map[string]PendingEvent)
+	store.Range(func(id string, pending PendingEvent) bool {
+		timeout := pending.Timeout
+		if timeout == 0 {
+			timeout = th.maxTimeout
+		}
+		if now.Sub(pending.ReceivedAt) > timeout {
+			stale[id] = pending
 		}
+		return true
+	})
+	return stale
+}
+
+// spanTimeout returns the configured span timeout, defaulting to 5 minutes.
+func spanTimeout(tc traceConfig) time.Duration {
+	if tc.SpanTimeout == 0 {
+		return 5 * time.Minute
 	}
+	return tc.SpanTimeout
 }
 
-// Close stops the cleanup goroutine and discards pending starts and ends.
+// endKey joins the trace config's end signal names for the composite key.
+// A single end yields just its name, so keys match those of a one-end config.
+func endKey(tc traceConfig) string {
+	return strings.Join(tc.EndSignalNames, "|")
+}
+
+// pendingContext returns the stored event's context, or context.Background()
+// if the store could not keep it (e.g. it was reloaded after a restart).
+func pendingContext(ev PendingEvent) context.Context {
+	if ev.Context == nil {
+		return context.Background()
+	}
+	return ev.Context
+}
+
+// Close stops the cleanup goroutine and discards pending starts and ends held
+// in the default in-memory stores. Custom stores are left intact so their
+// events outlive the handler.
 func (th *tracesHandler) Close() {
 	if th == nil {
 		return
@@ -152,20 +289,29 @@ func (th *tracesHandler) Close() {
 
 	close(th.stopCleanup)
 
-	// Discard all pending starts and ends
+	if th.pendingGauge != nil {
+		_ = th.pendingGauge.Unregister() //nolint:errcheck // best-effort cleanup
+	}
+
+	// Discard pending starts and ends in the default stores
 	th.mu.Lock()
 	defer th.mu.Unlock()
 
-	for id := range th.pendingStarts {
-		delete(th.pendingStarts, id)
-	}
-	for id := range th.pendingEnds {
-		delete(th.pendingEnds, id)
+	for _, store := range th.ownedStores {
+		var ids []string
+		store.Range(func(id string, _ PendingEvent) bool {
+			ids = append(ids, id)
+			return true
+		})
+		for _, id := range ids {
+			store.Delete(id)
+		}
 	}
 }
 
 // handleEvent checks if the event starts or ends a configured trace span.
-func (th *tracesHandler) handleEvent(ctx context.Context, e *capitan.Event) {
+// static holds instance-wide attributes added to any span created.
+func (th *tracesHandler) handleEvent(ctx context.Context, e *capitan.Event, static []attribute.KeyValue) {
 	if th == nil {
 		return
 	}
@@ -174,25 +320,22 @@ func (th *tracesHandler) handleEvent(ctx context.Context, e *capitan.Event) {
 
 	// Check each trace configuration (match by signal name)
 	for _, tc := range th.config {
-		switch signalName {
-		case tc.StartSignalName:
-			th.handleStart(ctx, e, tc)
-		case tc.EndSignalName:
-			th.handleEnd(ctx, e, tc)
+		switch {
+		case signalName == tc.StartSignalName:
+			th.handleStart(ctx, e, tc, static)
+		case slices.Contains(tc.EndSignalNames, signalName):
+			th.handleEnd(ctx, e, tc, static)
 		}
 	}
 }
 
 // handleStart stores the start event data or creates span if end already received.
-func (th *tracesHandler) handleStart(ctx context.Context, e *capitan.Event, tc traceConfig) {
-	// Determine span name for diagnostics
-	spanName := tc.SpanName
-	if spanName == "" {
-		spanName = tc.StartSignalName
-	}
+func (th *tracesHandler) handleStart(ctx context.Context, e *capitan.Event, tc traceConfig, static []attribute.KeyValue) {
+	// The span is named from the start event, whichever event arrives first
+	spanName := th.startSpanName(tc, e)
 
 	// Extract correlation ID from event (by key name)
-	correlationID := extractStringFieldByName(e, tc.CorrelationKeyName)
+	correlationID := extractCorrelationID(e, tc.CorrelationKeyName)
 	if correlationID == "" {
 		// Emit diagnostic for missing correlation ID
 		th.internal.emit(ctx, SignalTraceCorrelationMissing,
@@ -204,52 +347,116 @@ func (th *tracesHandler) handleStart(ctx context.Context, e *capitan.Event, tc t
 	}
 
 	// Create composite key to prevent collisions between different trace configs
-	compositeKey := th.makeCompositeKey(correlationID, tc.StartSignalName, tc.EndSignalName)
+	compositeKey := th.makeCompositeKey(correlationID, tc.StartSignalName, endKey(tc))
 
 	th.mu.Lock()
 	defer th.mu.Unlock()
 
+	// Duplicate start for a pending span - keep the earliest start.
+	// A closed-span marker means the ID is being reused for a new span.
+	if existing, ok := th.pendingStarts.Get(compositeKey); ok {
+		if existing.Ended {
+			th.pendingStarts.Delete(compositeKey)
+		} else {
+			th.internal.emit(ctx, SignalTraceDuplicateStart,
+				internalCorrelationID.Field(correlationID),
+				internalSpanName.Field(spanName),
+			)
+			return
+		}
+	}
+
+	// Sampled out - drop the pair without creating a span
+	if !sampled(correlationID, tc.SampleRate) {
+		if _, ok := th.pendingEnds.Get(compositeKey); ok {
+			th.pendingEnds.Delete(compositeKey)
+			th.markClosed(compositeKey, tc, PendingEvent{SpanName: spanName, CorrelationID: correlationID, Unsampled: true})
+			return
+		}
+
+		// Remember the decision so the matching end is discarded cheaply.
+		// Stale markers are removed by cleanupStaleSpans like any pending start.
+		if th.pendingFull(ctx, th.pendingStarts.Len(), e, correlationID, spanName) {
+			return
+		}
+		th.pendingStarts.Set(compositeKey, PendingEvent{
+			Context:       ctx,
+			SpanName:      spanName,
+			CorrelationID: correlationID,
+			ReceivedAt:    th.now(),
+			Timeout:       spanTimeout(tc),
+			Unsampled:     true,
+		})
+		return
+	}
+
 	// Check if end event already arrived
-	if pendingEnd, ok := th.pendingEnds[compositeKey]; ok {
+	if pendingEnd, ok := th.pendingEnds.Get(compositeKey); ok {
 		// End arrived first - create span now with both timestamps
 		// e is the start event, pendingEnd has the end event
-		delete(th.pendingEnds, compositeKey)
+		th.pendingEnds.Delete(compositeKey)
+		th.markClosed(compositeKey, tc, PendingEvent{SpanName: spanName, CorrelationID: correlationID})
 		th.mu.Unlock()
 
-		_, span := th.tracer.Start(ctx, spanName, trace.WithTimestamp(e.Timestamp()))
-
-		// Add context attributes if configured
+		// Extract context attributes if configured
+		var contextAttrs []attribute.KeyValue
 		if len(th.contextKeys) > 0 {
-			contextAttrs := extractContextValuesForMetrics(ctx, th.contextKeys)
-			span.SetAttributes(contextAttrs...)
+			contextAttrs = th.spanContextAttrs(ctx, pendingContext(pendingEnd), tc)
 		}
 
-		span.End(trace.WithTimestamp(pendingEnd.endTime))
+		_, span := th.tracer.Start(ctx, spanName,
+			trace.WithTimestamp(e.Timestamp()),
+			trace.WithAttributes(th.spanAttributes(static, contextAttrs)...))
+		th.checkResource(ctx, span)
+
+		endTime := th.spanEndTime(ctx, e.Timestamp(), pendingEnd.Time, correlationID, spanName)
+		span.End(trace.WithTimestamp(endTime))
+		th.recordDuration(ctx, tc, endTime.Sub(e.Timestamp()), static, contextAttrs)
 
 		th.mu.Lock()
 		return
 	}
 
 	// No end yet - store start event data
-	th.pendingStarts[compositeKey] = &pendingSpan{
-		startTime:     e.Timestamp(),
-		startCtx:      ctx,
-		spanName:      spanName,
-		correlationID: correlationID,
-		receivedAt:    time.Now(),
+	if th.pendingFull(ctx, th.pendingStarts.Len(), e, correlationID, spanName) {
+		return
+	}
+	th.pendingStarts.Set(compositeKey, PendingEvent{
+		Time:          e.Timestamp(),
+		Context:       ctx,
+		SpanName:      spanName,
+		CorrelationID: correlationID,
+		ReceivedAt:    th.now(),
+		Timeout:       spanTimeout(tc),
+	})
+}
+
+// startSpanName returns the span name for tc. A SpanNameTemplate is rendered
+// from the start event's fields; without one, or if a placeholder's field is
+// missing, SpanName is used, then the start signal name.
+func (th *tracesHandler) startSpanName(tc traceConfig, e *capitan.Event) string {
+	if bt, ok := th.spanNames[tc.SpanNameTemplate]; ok {
+		if name, ok := bt.render(fieldsToAttributes(e.Fields(), false, false, 0).attrs); ok {
+			return name
+		}
 	}
+	if tc.SpanName == "" {
+		return tc.StartSignalName
+	}
+	return tc.SpanName
 }
 
 // handleEnd stores the end event data or creates span if start already received.
-func (th *tracesHandler) handleEnd(ctx context.Context, e *capitan.Event, tc traceConfig) {
-	// Determine span name for diagnostics
+func (th *tracesHandler) handleEnd(ctx context.Context, e *capitan.Event, tc traceConfig, static []attribute.KeyValue) {
+	// Determine span name for diagnostics. A span name template is not
+	// rendered here; the span itself is named from the start event.
 	spanName := tc.SpanName
 	if spanName == "" {
 		spanName = tc.StartSignalName
 	}
 
 	// Extract correlation ID from event (by key name)
-	correlationID := extractStringFieldByName(e, tc.CorrelationKeyName)
+	correlationID := extractCorrelationID(e, tc.CorrelationKeyName)
 	if correlationID == "" {
 		// Emit diagnostic for missing correlation ID
 		th.internal.emit(ctx, SignalTraceCorrelationMissing,
@@ -261,40 +468,147 @@ func (th *tracesHandler) handleEnd(ctx context.Context, e *capitan.Event, tc tra
 	}
 
 	// Create composite key to prevent collisions between different trace configs
-	compositeKey := th.makeCompositeKey(correlationID, tc.StartSignalName, tc.EndSignalName)
+	compositeKey := th.makeCompositeKey(correlationID, tc.StartSignalName, endKey(tc))
 
 	th.mu.Lock()
 	defer th.mu.Unlock()
 
 	// Check if start event already arrived
-	if pendingStart, ok := th.pendingStarts[compositeKey]; ok {
+	if pendingStart, ok := th.pendingStarts.Get(compositeKey); ok {
+		// Another end already closed the span - the first end wins
+		if pendingStart.Ended {
+			th.pendingStarts.Delete(compositeKey)
+			if !pendingStart.Unsampled {
+				th.emitDuplicateEnd(ctx, e, correlationID, spanName)
+			}
+			return
+		}
+
 		// Start arrived first - create span now with both timestamps
-		delete(th.pendingStarts, compositeKey)
+		th.pendingStarts.Delete(compositeKey)
+		th.markClosed(compositeKey, tc, pendingStart)
+		if pendingStart.Unsampled {
+			return
+		}
 		th.mu.Unlock()
 
-		_, span := th.tracer.Start(pendingStart.startCtx, pendingStart.spanName,
-			trace.WithTimestamp(pendingStart.startTime))
-
-		// Add context attributes if configured (use start context)
+		// Extract context attributes if configured
+		startCtx := pendingContext(pendingStart)
+		var contextAttrs []attribute.KeyValue
 		if len(th.contextKeys) > 0 {
-			contextAttrs := extractContextValuesForMetrics(pendingStart.startCtx, th.contextKeys)
-			span.SetAttributes(contextAttrs...)
+			contextAttrs = th.spanContextAttrs(startCtx, ctx, tc)
 		}
 
-		span.End(trace.WithTimestamp(e.Timestamp()))
+		_, span := th.tracer.Start(startCtx, pendingStart.SpanName,
+			trace.WithTimestamp(pendingStart.Time),
+			trace.WithAttributes(th.spanAttributes(static, contextAttrs)...))
+		th.checkResource(ctx, span)
+
+		endTime := th.spanEndTime(ctx, pendingStart.Time, e.Timestamp(), correlationID, pendingStart.SpanName)
+		span.End(trace.WithTimestamp(endTime))
+		th.recordDuration(ctx, tc, endTime.Sub(pendingStart.Time), static, contextAttrs)
 
 		th.mu.Lock()
 		return
 	}
 
+	// Another end is already waiting for the start - the first end wins
+	if len(tc.EndSignalNames) > 1 {
+		if _, ok := th.pendingEnds.Get(compositeKey); ok {
+			th.emitDuplicateEnd(ctx, e, correlationID, spanName)
+			return
+		}
+	}
+
 	// No start yet - store end event data
-	th.pendingEnds[compositeKey] = &pendingEnd{
-		endTime:       e.Timestamp(),
-		endCtx:        ctx,
-		correlationID: correlationID,
-		spanName:      spanName,
-		receivedAt:    time.Now(),
+	if th.pendingFull(ctx, th.pendingEnds.Len(), e, correlationID, spanName) {
+		return
+	}
+	th.pendingEnds.Set(compositeKey, PendingEvent{
+		Time:          e.Timestamp(),
+		Context:       ctx,
+		CorrelationID: correlationID,
+		SpanName:      spanName,
+		ReceivedAt:    th.now(),
+		Timeout:       spanTimeout(tc),
+	})
+}
+
+// markClosed leaves a closed-span marker under key for trace configs with
+// several end signals, so an end arriving after the span closed is reported as
+// a duplicate instead of waiting for a start. No-op for single-end configs, or
+// when max_pending is reached. Caller must hold th.mu.
+func (th *tracesHandler) markClosed(key string, tc traceConfig, pending PendingEvent) {
+	if len(tc.EndSignalNames) < 2 {
+		return
+	}
+	if th.maxPending > 0 && th.pendingStarts.Len() >= th.maxPending {
+		return
+	}
+	th.pendingStarts.Set(key, PendingEvent{
+		SpanName:      pending.SpanName,
+		CorrelationID: pending.CorrelationID,
+		ReceivedAt:    th.now(),
+		Timeout:       spanTimeout(tc),
+		Unsampled:     pending.Unsampled,
+		Ended:         true,
+	})
+}
+
+// emitDuplicateEnd reports an end event for a span another end already closed.
+func (th *tracesHandler) emitDuplicateEnd(ctx context.Context, e *capitan.Event, correlationID, spanName string) {
+	th.internal.emit(ctx, SignalTraceDuplicateEnd,
+		internalSignal.Field(e.Signal().Name()),
+		internalCorrelationID.Field(correlationID),
+		internalSpanName.Field(spanName),
+	)
+}
+
+// pendingFull reports whether a pending map of size n has reached max_pending,
+// emitting SignalTracePendingOverflow for the rejected event if so.
+// The newest event is rejected rather than evicting the oldest, so pairs that
+// are already waiting can still complete. Caller must hold th.mu.
+func (th *tracesHandler) pendingFull(ctx context.Context, n int, e *capitan.Event, correlationID, spanName string) bool {
+	if th.maxPending == 0 || n < th.maxPending {
+		return false
 	}
+
+	th.internal.emit(ctx, SignalTracePendingOverflow,
+		internalSignal.Field(e.Signal().Name()),
+		internalCorrelationID.Field(correlationID),
+		internalSpanName.Field(spanName),
+		internalMaxPending.Field(strconv.Itoa(th.maxPending)),
+	)
+	return true
+}
+
+// recordDuration records a completed span's duration in milliseconds into the
+// trace config's duration histogram, if one is configured. The attributes
+// match the span's: static attributes first, then context attributes.
+func (th *tracesHandler) recordDuration(ctx context.Context, tc traceConfig, d time.Duration, static, contextAttrs []attribute.KeyValue) {
+	hist, ok := th.durationMetrics[tc.DurationMetric]
+	if !ok {
+		return
+	}
+
+	attrs := slices.Concat(static, contextAttrs)
+	hist.Record(ctx, float64(d)/float64(time.Millisecond), metric.WithAttributes(attrs...))
+}
+
+// spanEndTime returns the end timestamp for a span. If the end event was emitted
+// before the start (clock skew or genuine reordering), the end is clamped to the
+// start so the span has zero rather than negative duration.
+func (th *tracesHandler) spanEndTime(ctx context.Context, start, end time.Time, correlationID, spanName string) time.Time {
+	if !end.Before(start) {
+		return end
+	}
+
+	th.internal.emit(ctx, SignalTraceNegativeDuration,
+		internalCorrelationID.Field(correlationID),
+		internalSpanName.Field(spanName),
+		internalSkew.Field(start.Sub(end).String()),
+	)
+	return start
 }
 
 // makeCompositeKey creates a unique key combining correlation ID and signal names.
@@ -303,20 +617,148 @@ func (*tracesHandler) makeCompositeKey(correlationID, startSignalName, endSignal
 	return correlationID + ":" + startSignalName + ":" + endSignalName
 }
 
-// extractStringFieldByName gets a string field value from the event fields by key name.
-func extractStringFieldByName(e *capitan.Event, keyName string) string {
+// sampled reports whether the pair identified by correlationID should produce a span.
+//
+// The decision is a deterministic hash of the correlation ID, so start and end
+// events (and every aperture instance) agree without coordination.
+func sampled(correlationID string, rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(correlationID)) //nolint:errcheck // hash writes never fail
+	return float64(h.Sum64()) < rate*math.MaxUint64
+}
+
+// checkResource emits SignalServiceNameMissing if the span's resource has no
+// resolvable service.name. Only the first span exposing a resource is checked.
+func (th *tracesHandler) checkResource(ctx context.Context, span trace.Span) {
+	if th.resourceChecked.Load() {
+		return
+	}
+
+	// SDK recording spans expose their resource; noop and non-recording spans do not
+	rs, ok := span.(interface{ Resource() *resource.Resource })
+	if !ok || th.resourceChecked.Swap(true) {
+		return
+	}
+
+	if err := ValidateResource(rs.Resource()); err != nil {
+		th.internal.emit(ctx, SignalServiceNameMissing,
+			internalReason.Field(err.Error()),
+		)
+	}
+}
+
+// spanAttributes returns a span's attributes: static attributes, then context
+// attributes so they win on key collision. With sort_attributes, the result is
+// sorted by key.
+func (th *tracesHandler) spanAttributes(static, contextAttrs []attribute.KeyValue) []attribute.KeyValue {
+	if len(contextAttrs) == 0 && !th.sortAttributes {
+		return static
+	}
+
+	attrs := slices.Concat(static, contextAttrs)
+	if th.sortAttributes {
+		sortAttributes(attrs)
+	}
+	return attrs
+}
+
+// spanContextAttrs extracts the configured context keys for a span.
+//
+// Values come from the start context. With MergeEndContext, values from the end
+// context are appended so they win on key collision (the span keeps the last
+// value set for a key).
+func (th *tracesHandler) spanContextAttrs(startCtx, endCtx context.Context, tc traceConfig) []attribute.KeyValue {
+	attrs := extractContextValuesForMetrics(startCtx, th.contextKeys)
+	if tc.MergeEndContext {
+		attrs = append(attrs, extractContextValuesForMetrics(endCtx, th.contextKeys)...)
+	}
+	return attrs
+}
+
+// extractCorrelationID gets a correlation ID from the event fields by key name.
+//
+// String fields are used as-is. Integer fields are formatted in base 10, byte
+// fields are hex-encoded, and time fields are formatted as RFC 3339 with
+// nanoseconds in UTC, so start and end events carrying the same value under
+// the same key type always match, whatever the time's location. Custom types
+// (e.g. a typed UUID) are used via encoding.TextMarshaler or fmt.Stringer, in
+// that order. Other variants (floats, bools) cannot be compared reliably and
+// are treated as missing.
+func extractCorrelationID(e *capitan.Event, keyName string) string {
 	if keyName == "" {
 		return ""
 	}
 
 	for _, f := range e.Fields() {
-		// Match by key name and string variant
-		if f.Key().Name() == keyName && f.Variant() == capitan.VariantString {
+		if f.Key().Name() != keyName {
+			continue
+		}
+
+		switch f.Variant() {
+		case capitan.VariantString:
 			if gf, ok := f.(capitan.GenericField[string]); ok {
 				return gf.Get()
 			}
+		case capitan.VariantInt:
+			if gf, ok := f.(capitan.GenericField[int]); ok {
+				return strconv.FormatInt(int64(gf.Get()), 10)
+			}
+		case capitan.VariantInt32:
+			if gf, ok := f.(capitan.GenericField[int32]); ok {
+				return strconv.FormatInt(int64(gf.Get()), 10)
+			}
+		case capitan.VariantInt64:
+			if gf, ok := f.(capitan.GenericField[int64]); ok {
+				return strconv.FormatInt(gf.Get(), 10)
+			}
+		case capitan.VariantUint:
+			if gf, ok := f.(capitan.GenericField[uint]); ok {
+				return strconv.FormatUint(uint64(gf.Get()), 10)
+			}
+		case capitan.VariantUint32:
+			if gf, ok := f.(capitan.GenericField[uint32]); ok {
+				return strconv.FormatUint(uint64(gf.Get()), 10)
+			}
+		case capitan.VariantUint64:
+			if gf, ok := f.(capitan.GenericField[uint64]); ok {
+				return strconv.FormatUint(gf.Get(), 10)
+			}
+		case capitan.VariantBytes:
+			if gf, ok := f.(capitan.GenericField[[]byte]); ok {
+				return hex.EncodeToString(gf.Get())
+			}
+		case capitan.VariantTime:
+			if gf, ok := f.(capitan.GenericField[time.Time]); ok {
+				return gf.Get().UTC().Format(time.RFC3339Nano)
+			}
+		default:
+			return customCorrelationID(f.Value())
 		}
 	}
 
 	return ""
 }
+
+// customCorrelationID formats a custom-typed correlation value.
+// Returns empty if the type has no stable text form.
+func customCorrelationID(v any) string {
+	switch id := v.(type) {
+	case encoding.TextMarshaler:
+		text, err := id.MarshalText()
+		if err != nil {
+			return ""
+		}
+		return string(text)
+	case fmt.Stringer:
+		return id.String()
+	default:
+		return ""
+	}
+}
@@ -2,14 +2,51 @@ package aperture
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"sync"
 	"testing"
 	"time"
 
 	apertesting "github.com/zoobzio/aperture/testing"
 	"github.com/zoobzio/capitan"
+	"go.opentelemetry.io/otel/attribute"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
 )
 
+// fakeClock is a manually advanced clock for deterministic expiry tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// useFakeClock replaces the handler's clock with one starting at the current
+// time, and returns it.
+func useFakeClock(th *tracesHandler) *fakeClock {
+	clock := &fakeClock{now: time.Now()}
+	th.mu.Lock()
+	th.now = clock.Now
+	th.mu.Unlock()
+	return clock
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
 func TestTraceSpanCleanup(t *testing.T) {
 	ctx := context.Background()
 	cap := capitan.New()
@@ -32,6 +69,13 @@ func TestTraceSpanCleanup(t *testing.T) {
 				SpanName:       "http_request",
 				SpanTimeout:    "5s",
 			},
+			{
+				Start:          "batch.started",
+				End:            "batch.completed",
+				CorrelationKey: "batch_id",
+				SpanName:       "batch",
+				SpanTimeout:    "5m",
+			},
 		},
 	}
 
@@ -53,58 +97,77 @@ func TestTraceSpanCleanup(t *testing.T) {
 
 	// Manually insert old pending events to test cleanup logic
 	th.mu.Lock()
-	th.pendingStarts["old-start"] = &pendingSpan{
-		startTime:  time.Now(),
-		startCtx:   ctx,
-		spanName:   "old_span",
-		receivedAt: time.Now().Add(-10 * time.Second), // 10 seconds ago
-	}
-	th.pendingEnds["old-end"] = &pendingEnd{
-		endTime:    time.Now(),
-		endCtx:     ctx,
-		receivedAt: time.Now().Add(-10 * time.Second), // 10 seconds ago
-	}
-	th.pendingStarts["recent-start"] = &pendingSpan{
-		startTime:  time.Now(),
-		startCtx:   ctx,
-		spanName:   "recent_span",
-		receivedAt: time.Now().Add(-1 * time.Second), // 1 second ago
-	}
+	th.pendingStarts.Set("old-start", PendingEvent{
+		Time:       time.Now(),
+		Context:    ctx,
+		SpanName:   "old_span",
+		ReceivedAt: time.Now().Add(-10 * time.Second), // 10 seconds ago
+		Timeout:    5 * time.Second,
+	})
+	th.pendingEnds.Set("old-end", PendingEvent{
+		Time:       time.Now(),
+		Context:    ctx,
+		ReceivedAt: time.Now().Add(-10 * time.Second), // 10 seconds ago
+		Timeout:    5 * time.Second,
+	})
+	th.pendingStarts.Set("recent-start", PendingEvent{
+		Time:       time.Now(),
+		Context:    ctx,
+		SpanName:   "recent_span",
+		ReceivedAt: time.Now().Add(-1 * time.Second), // 1 second ago
+		Timeout:    5 * time.Second,
+	})
+	th.pendingStarts.Set("old-batch-start", PendingEvent{
+		Time:       time.Now(),
+		Context:    ctx,
+		SpanName:   "batch",
+		ReceivedAt: time.Now().Add(-10 * time.Second), // 10 seconds ago
+		Timeout:    5 * time.Minute,
+	})
 	th.mu.Unlock()
 
-	// Verify we have 3 pending events
+	// Verify we have 4 pending events
 	th.mu.Lock()
-	totalBefore := len(th.pendingStarts) + len(th.pendingEnds)
+	totalBefore := th.pendingStarts.Len() + th.pendingEnds.Len()
 	th.mu.Unlock()
-	if totalBefore != 3 {
-		t.Errorf("expected 3 pending events before cleanup, got %d", totalBefore)
+	if totalBefore != 4 {
+		t.Errorf("expected 4 pending events before cleanup, got %d", totalBefore)
 	}
 
-	// Run cleanup - should remove events older than 5 seconds
-	t.Logf("maxTimeout: %v", th.maxTimeout)
+	// Run cleanup - should remove events older than their own timeout,
+	// even though the longest configured timeout is 5 minutes
+	if th.maxTimeout != 5*time.Minute {
+		t.Errorf("expected max timeout of 5 minutes, got %v", th.maxTimeout)
+	}
+	if th.cleanupEvery != 5*time.Second {
+		t.Errorf("expected cleanup every 5 seconds, got %v", th.cleanupEvery)
+	}
 	th.cleanupStaleSpans()
 
 	// Verify old events removed, recent kept
 	th.mu.Lock()
-	startsAfter := len(th.pendingStarts)
-	endsAfter := len(th.pendingEnds)
+	startsAfter := th.pendingStarts.Len()
+	endsAfter := th.pendingEnds.Len()
 	totalAfter := startsAfter + endsAfter
 	th.mu.Unlock()
 
-	if totalAfter != 1 {
-		t.Errorf("expected 1 pending event after cleanup, got %d (starts: %d, ends: %d)",
+	if totalAfter != 2 {
+		t.Errorf("expected 2 pending events after cleanup, got %d (starts: %d, ends: %d)",
 			totalAfter, startsAfter, endsAfter)
 	}
 
-	// Verify the recent one is still there
+	// Verify the recent one and the long-timeout one are still there
 	th.mu.Lock()
-	if _, ok := th.pendingStarts["recent-start"]; !ok {
+	if _, ok := th.pendingStarts.Get("recent-start"); !ok {
 		t.Error("expected recent-start to still be present")
 	}
-	if _, ok := th.pendingStarts["old-start"]; ok {
+	if _, ok := th.pendingStarts.Get("old-batch-start"); !ok {
+		t.Error("expected old-batch-start to be kept by its 5 minute timeout")
+	}
+	if _, ok := th.pendingStarts.Get("old-start"); ok {
 		t.Error("expected old-start to be cleaned up")
 	}
-	if _, ok := th.pendingEnds["old-end"]; ok {
+	if _, ok := th.pendingEnds.Get("old-end"); ok {
 		t.Error("expected old-end to be cleaned up")
 	}
 	th.mu.Unlock()
@@ -166,10 +229,10 @@ func TestTraceSpanCompletesBeforeTimeout(t *testing.T) {
 	// Verify span was completed (both pending maps should be empty)
 	th := sh.capitanObserver.tracesHandler
 	th.mu.Lock()
-	totalPending := len(th.pendingStarts) + len(th.pendingEnds)
+	totalPending := th.pendingStarts.Len() + th.pendingEnds.Len()
 	if totalPending != 0 {
 		t.Errorf("expected 0 pending events after completion, got %d (starts: %d, ends: %d)",
-			totalPending, len(th.pendingStarts), len(th.pendingEnds))
+			totalPending, th.pendingStarts.Len(), th.pendingEnds.Len())
 	}
 	th.mu.Unlock()
 }
@@ -269,7 +332,7 @@ func TestTraceCloseEndsAllSpans(t *testing.T) {
 
 	th := sh.capitanObserver.tracesHandler
 	th.mu.Lock()
-	totalPending := len(th.pendingStarts) + len(th.pendingEnds)
+	totalPending := th.pendingStarts.Len() + th.pendingEnds.Len()
 	th.mu.Unlock()
 
 	if totalPending != 3 {
@@ -280,7 +343,7 @@ func TestTraceCloseEndsAllSpans(t *testing.T) {
 	sh.Close()
 
 	th.mu.Lock()
-	remainingPending := len(th.pendingStarts) + len(th.pendingEnds)
+	remainingPending := th.pendingStarts.Len() + th.pendingEnds.Len()
 	th.mu.Unlock()
 
 	if remainingPending != 0 {
@@ -357,11 +420,780 @@ func TestTraceCompositeKeyPreventsCollisions(t *testing.T) {
 	// Both spans should complete without collision
 	th := sh.capitanObserver.tracesHandler
 	th.mu.Lock()
-	totalPending := len(th.pendingStarts) + len(th.pendingEnds)
+	totalPending := th.pendingStarts.Len() + th.pendingEnds.Len()
 	th.mu.Unlock()
 
 	if totalPending != 0 {
 		t.Errorf("expected 0 pending events (both spans completed), got %d (starts: %d, ends: %d)",
-			totalPending, len(th.pendingStarts), len(th.pendingEnds))
+			totalPending, th.pendingStarts.Len(), th.pendingEnds.Len())
+	}
+}
+
+func TestTraceSampleRate(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	recorder := tracetest.NewSpanRecorder()
+	traceProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer traceProvider.Shutdown(ctx)
+
+	requestStarted := capitan.NewSignal("request.started", "Request Started")
+	requestCompleted := capitan.NewSignal("request.completed", "Request Completed")
+	requestIDKey := capitan.NewStringKey("request_id")
+
+	schema := Schema{
+		Traces: []TraceSchema{
+			{
+				Start:          "request.started",
+				End:            "request.completed",
+				CorrelationKey: "request_id",
+				SpanName:       "http_request",
+				SampleRate:     0.1,
+			},
+		},
+	}
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), traceProvider)
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(schema)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	const pairs = 1000
+	wantSpans := 0
+	for i := 0; i < pairs; i++ {
+		id := fmt.Sprintf("REQ-%d", i)
+		if sampled(id, 0.1) {
+			wantSpans++
+		}
+
+		// Alternate ordering so both start-first and end-first paths are exercised
+		if i%2 == 0 {
+			cap.Emit(ctx, requestStarted, requestIDKey.Field(id))
+			cap.Emit(ctx, requestCompleted, requestIDKey.Field(id))
+		} else {
+			cap.Emit(ctx, requestCompleted, requestIDKey.Field(id))
+			cap.Emit(ctx, requestStarted, requestIDKey.Field(id))
+		}
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	if wantSpans == 0 || wantSpans == pairs {
+		t.Fatalf("expected a partial sample, got %d of %d", wantSpans, pairs)
+	}
+	if got := len(recorder.Ended()); got != wantSpans {
+		t.Errorf("expected %d spans, got %d", wantSpans, got)
+	}
+
+	// Unsampled pairs must not leak
+	th := sh.capitanObserver.tracesHandler
+	th.mu.Lock()
+	starts, ends := th.pendingStarts.Len(), th.pendingEnds.Len()
+	th.mu.Unlock()
+	if starts != 0 || ends != 0 {
+		t.Errorf("expected no pending state, got %d starts and %d ends", starts, ends)
+	}
+}
+
+func TestTraceSampleRate_UnsampledStartCleanedUp(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(Schema{
+		Traces: []TraceSchema{
+			{
+				Start:          "request.started",
+				End:            "request.completed",
+				CorrelationKey: "request_id",
+				SpanTimeout:    "1s",
+				SampleRate:     0.1,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	// Find an ID that is sampled out
+	id := ""
+	for i := 0; ; i++ {
+		id = fmt.Sprintf("REQ-%d", i)
+		if !sampled(id, 0.1) {
+			break
+		}
+	}
+
+	th := sh.capitanObserver.tracesHandler
+	clock := useFakeClock(th)
+
+	requestStarted := capitan.NewSignal("request.started", "Request Started")
+	requestIDKey := capitan.NewStringKey("request_id")
+	cap.Emit(ctx, requestStarted, requestIDKey.Field(id))
+	sh.Flush(ctx)
+
+	th.mu.Lock()
+	key := th.makeCompositeKey(id, "request.started", "request.completed")
+	pending, ok := th.pendingStarts.Get(key)
+	th.mu.Unlock()
+
+	if !ok {
+		t.Fatal("expected unsampled start to be tracked")
+	}
+	if !pending.Unsampled {
+		t.Error("expected pending start to be marked unsampled")
+	}
+
+	// Age the marker past the timeout
+	clock.Advance(2 * time.Second)
+	th.cleanupStaleSpans()
+
+	th.mu.Lock()
+	remaining := th.pendingStarts.Len()
+	th.mu.Unlock()
+	if remaining != 0 {
+		t.Errorf("expected unsampled start to be cleaned up, %d remaining", remaining)
+	}
+}
+
+func TestSampled(t *testing.T) {
+	if !sampled("any", 1) {
+		t.Error("rate 1 should sample everything")
+	}
+	if sampled("any", 0) {
+		t.Error("rate 0 should sample nothing")
+	}
+
+	// Deterministic per correlation ID
+	for i := 0; i < 100; i++ {
+		id := fmt.Sprintf("REQ-%d", i)
+		if sampled(id, 0.5) != sampled(id, 0.5) {
+			t.Fatalf("sampling decision for %q is not deterministic", id)
+		}
+	}
+}
+
+func TestTraceInt64CorrelationKey(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	recorder := tracetest.NewSpanRecorder()
+	traceProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer traceProvider.Shutdown(ctx)
+
+	requestStarted := capitan.NewSignal("request.started", "Request Started")
+	requestCompleted := capitan.NewSignal("request.completed", "Request Completed")
+	requestIDKey := capitan.NewInt64Key("request_id")
+
+	schema := Schema{
+		Traces: []TraceSchema{
+			{
+				Start:          "request.started",
+				End:            "request.completed",
+				CorrelationKey: "request_id",
+				SpanName:       "http_request",
+			},
+		},
+	}
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), traceProvider)
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(schema)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	// Start-first and end-first pairs
+	cap.Emit(ctx, requestStarted, requestIDKey.Field(int64(1001)))
+	cap.Emit(ctx, requestCompleted, requestIDKey.Field(int64(1001)))
+	cap.Emit(ctx, requestCompleted, requestIDKey.Field(int64(1002)))
+	cap.Emit(ctx, requestStarted, requestIDKey.Field(int64(1002)))
+
+	// Non-matching IDs stay pending
+	cap.Emit(ctx, requestStarted, requestIDKey.Field(int64(2001)))
+	cap.Emit(ctx, requestCompleted, requestIDKey.Field(int64(2002)))
+
+	time.Sleep(100 * time.Millisecond)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 matched spans, got %d", len(spans))
+	}
+	for _, span := range spans {
+		if span.Name() != "http_request" {
+			t.Errorf("expected span name 'http_request', got %q", span.Name())
+		}
+	}
+}
+
+// testTextID is a custom correlation type with a text encoding.
+type testTextID struct{ n int }
+
+func (id testTextID) MarshalText() ([]byte, error) { return []byte(fmt.Sprintf("text-%d", id.n)), nil }
+func (id testTextID) String() string               { return "ignored" }
+
+// testStringID is a custom correlation type with only a String method.
+type testStringID int
+
+func (id testStringID) String() string { return fmt.Sprintf("sid-%d", int(id)) }
+
+func TestExtractCorrelationID(t *testing.T) {
+	cap := capitan.New()
+	sig := capitan.NewSignal("test.signal", "Test")
+
+	tests := []struct {
+		name  string
+		field capitan.Field
+		want  string
+	}{
+		{"string", capitan.NewStringKey("id").Field("REQ-1"), "REQ-1"},
+		{"int", capitan.NewIntKey("id").Field(-7), "-7"},
+		{"int32", capitan.NewInt32Key("id").Field(int32(32)), "32"},
+		{"int64", capitan.NewInt64Key("id").Field(int64(1001)), "1001"},
+		{"uint", capitan.NewUintKey("id").Field(uint(8)), "8"},
+		{"uint32", capitan.NewUint32Key("id").Field(uint32(9)), "9"},
+		{"uint64", capitan.NewUint64Key("id").Field(uint64(18446744073709551615)), "18446744073709551615"},
+		{"bytes", capitan.NewBytesKey("id").Field([]byte{0xde, 0xad, 0xbe, 0xef}), "deadbeef"},
+		{"empty bytes", capitan.NewBytesKey("id").Field([]byte{}), ""},
+		{"time", capitan.NewTimeKey("id").Field(time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)), "2024-01-02T03:04:05.000000006Z"},
+		{"time in other zone", capitan.NewTimeKey("id").Field(time.Date(2024, 1, 2, 5, 4, 5, 6, time.FixedZone("EET", 2*60*60))), "2024-01-02T03:04:05.000000006Z"},
+		{"float unsupported", capitan.NewFloat64Key("id").Field(1.5), ""},
+		{"bool unsupported", capitan.NewBoolKey("id").Field(true), ""},
+		{"text marshaler", capitan.NewKey[testTextID]("id", "test.TextID").Field(testTextID{7}), "text-7"},
+		{"stringer", capitan.NewKey[testStringID]("id", "test.StringID").Field(testStringID(42)), "sid-42"},
+		{"custom unsupported", capitan.NewKey[struct{ N int }]("id", "test.Plain").Field(struct{ N int }{1}), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := make(chan string, 1)
+			listener := cap.Hook(sig, func(_ context.Context, e *capitan.Event) {
+				results <- extractCorrelationID(e, "id")
+			})
+			defer listener.Close()
+
+			cap.Emit(context.Background(), sig, tt.field)
+
+			select {
+			case got := <-results:
+				if got != tt.want {
+					t.Errorf("extractCorrelationID() = %q, want %q", got, tt.want)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("timed out waiting for event")
+			}
+		})
+	}
+}
+
+func TestExtractCorrelationID_NoCollisions(t *testing.T) {
+	cap := capitan.New()
+	sig := capitan.NewSignal("test.signal", "Test")
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// Each pair holds distinct values that a lossy encoding would merge
+	pairs := []struct {
+		name string
+		a, b capitan.Field
+	}{
+		{"bytes regrouped", capitan.NewBytesKey("id").Field([]byte{0x01, 0x23}), capitan.NewBytesKey("id").Field([]byte{0x12, 0x03})},
+		{"bytes leading zero", capitan.NewBytesKey("id").Field([]byte{0x00}), capitan.NewBytesKey("id").Field([]byte{0x00, 0x00})},
+		{"time one nanosecond apart", capitan.NewTimeKey("id").Field(at), capitan.NewTimeKey("id").Field(at.Add(time.Nanosecond))},
+		{"time same wall clock, other zone", capitan.NewTimeKey("id").Field(at), capitan.NewTimeKey("id").Field(time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("EET", 2*60*60)))},
+	}
+
+	extract := func(t *testing.T, f capitan.Field) string {
+		t.Helper()
+		results := make(chan string, 1)
+		listener := cap.Hook(sig, func(_ context.Context, e *capitan.Event) {
+			results <- extractCorrelationID(e, "id")
+		})
+		defer listener.Close()
+
+		cap.Emit(context.Background(), sig, f)
+
+		select {
+		case got := <-results:
+			return got
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for event")
+			return ""
+		}
+	}
+
+	for _, tt := range pairs {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := extract(t, tt.a), extract(t, tt.b)
+			if a == "" || b == "" {
+				t.Fatalf("expected correlation IDs, got %q and %q", a, b)
+			}
+			if a == b {
+				t.Errorf("distinct values collide on %q", a)
+			}
+			if again := extract(t, tt.a); again != a {
+				t.Errorf("encoding not stable: %q then %q", a, again)
+			}
+		})
+	}
+}
+
+func TestTraceTimeCorrelationKey(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	recorder := tracetest.NewSpanRecorder()
+	traceProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer traceProvider.Shutdown(ctx)
+
+	batchStarted := capitan.NewSignal("batch.started", "Batch Started")
+	batchCompleted := capitan.NewSignal("batch.completed", "Batch Completed")
+	scheduledKey := capitan.NewTimeKey("scheduled_at")
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), traceProvider)
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(Schema{
+		Traces: []TraceSchema{
+			{Start: "batch.started", End: "batch.completed", CorrelationKey: "scheduled_at"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	// The same instant reported in two locations correlates
+	scheduled := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	cap.Emit(ctx, batchStarted, scheduledKey.Field(scheduled))
+	cap.Emit(ctx, batchCompleted, scheduledKey.Field(scheduled.In(time.FixedZone("EST", -5*60*60))))
+
+	sh.Flush(ctx)
+
+	if got := len(recorder.Ended()); got != 1 {
+		t.Fatalf("expected 1 span, got %d", got)
+	}
+}
+
+func TestTraceSpanNameTemplate(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	recorder := tracetest.NewSpanRecorder()
+	traceProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer traceProvider.Shutdown(ctx)
+
+	requestStarted := capitan.NewSignal("request.started", "Request Started")
+	requestCompleted := capitan.NewSignal("request.completed", "Request Completed")
+	requestIDKey := capitan.NewStringKey("request_id")
+	methodKey := capitan.NewStringKey("method")
+	routeKey := capitan.NewStringKey("route")
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), traceProvider)
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(Schema{
+		Traces: []TraceSchema{
+			{
+				Start:            "request.started",
+				End:              "request.completed",
+				CorrelationKey:   "request_id",
+				SpanNameTemplate: "{method} {route}",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	// Start first, end first, and a start missing the route field
+	cap.Emit(ctx, requestStarted, requestIDKey.Field("REQ-1"), methodKey.Field("GET"), routeKey.Field("/orders"))
+	cap.Emit(ctx, requestCompleted, requestIDKey.Field("REQ-1"), methodKey.Field("DELETE"))
+	cap.Emit(ctx, requestCompleted, requestIDKey.Field("REQ-2"))
+	cap.Emit(ctx, requestStarted, requestIDKey.Field("REQ-2"), methodKey.Field("POST"), routeKey.Field("/orders/{id}"))
+	cap.Emit(ctx, requestStarted, requestIDKey.Field("REQ-3"), methodKey.Field("GET"))
+	cap.Emit(ctx, requestCompleted, requestIDKey.Field("REQ-3"))
+
+	sh.Flush(ctx)
+
+	var names []string
+	for _, span := range recorder.Ended() {
+		names = append(names, span.Name())
+	}
+	slices.Sort(names)
+	want := []string{"GET /orders", "POST /orders/{id}", "request.started"}
+	if !slices.Equal(names, want) {
+		t.Errorf("span names = %q, want %q", names, want)
+	}
+}
+
+func TestTraceSpanNameLiteral(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	recorder := tracetest.NewSpanRecorder()
+	traceProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer traceProvider.Shutdown(ctx)
+
+	requestStarted := capitan.NewSignal("request.started", "Request Started")
+	requestCompleted := capitan.NewSignal("request.completed", "Request Completed")
+	jobStarted := capitan.NewSignal("job.started", "Job Started")
+	jobCompleted := capitan.NewSignal("job.completed", "Job Completed")
+	requestIDKey := capitan.NewStringKey("request_id")
+	idKey := capitan.NewStringKey("id")
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), traceProvider)
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	// Braces in span_name are not placeholders, even when unbalanced
+	err = sh.Apply(Schema{
+		Traces: []TraceSchema{
+			{
+				Start:          "request.started",
+				End:            "request.completed",
+				CorrelationKey: "request_id",
+				SpanName:       "GET /users/{id}",
+			},
+			{
+				Start:          "job.started",
+				End:            "job.completed",
+				CorrelationKey: "request_id",
+				SpanName:       "job {",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	cap.Emit(ctx, requestStarted, requestIDKey.Field("REQ-1"), idKey.Field("42"))
+	cap.Emit(ctx, requestCompleted, requestIDKey.Field("REQ-1"))
+	cap.Emit(ctx, jobStarted, requestIDKey.Field("JOB-1"))
+	cap.Emit(ctx, jobCompleted, requestIDKey.Field("JOB-1"))
+
+	sh.Flush(ctx)
+
+	var names []string
+	for _, span := range recorder.Ended() {
+		names = append(names, span.Name())
+	}
+	slices.Sort(names)
+	want := []string{"GET /users/{id}", "job {"}
+	if !slices.Equal(names, want) {
+		t.Errorf("span names = %q, want %q", names, want)
+	}
+}
+
+func TestTraceMergeEndContext(t *testing.T) {
+	type ctxKey string
+	userKey := ctxKey("user_id")
+	statusKey := ctxKey("status")
+	tenantKey := ctxKey("tenant")
+
+	tests := []struct {
+		name     string
+		merge    bool
+		endFirst bool
+		want     map[string]string
+	}{
+		{"start only, start first", false, false, map[string]string{"user_id": "u1", "status": "pending"}},
+		{"start only, end first", false, true, map[string]string{"user_id": "u1", "status": "pending"}},
+		{"merged, start first", true, false, map[string]string{"user_id": "u1", "status": "done", "tenant": "t1"}},
+		{"merged, end first", true, true, map[string]string{"user_id": "u1", "status": "done", "tenant": "t1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cap := capitan.New()
+
+			recorder := tracetest.NewSpanRecorder()
+			traceProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+			defer traceProvider.Shutdown(ctx)
+
+			requestStarted := capitan.NewSignal("request.started", "Request Started")
+			requestCompleted := capitan.NewSignal("request.completed", "Request Completed")
+			requestIDKey := capitan.NewStringKey("request_id")
+
+			sh, err := New(cap, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), traceProvider)
+			if err != nil {
+				t.Fatalf("failed to create Aperture: %v", err)
+			}
+			defer sh.Close()
+
+			sh.RegisterContextKey("user_id", userKey)
+			sh.RegisterContextKey("status", statusKey)
+			sh.RegisterContextKey("tenant", tenantKey)
+
+			err = sh.Apply(Schema{
+				Traces: []TraceSchema{
+					{
+						Start:           "request.started",
+						End:             "request.completed",
+						CorrelationKey:  "request_id",
+						MergeEndContext: tt.merge,
+					},
+				},
+				Context: &ContextSchema{
+					Traces: []string{"user_id", "status", "tenant"},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+
+			startCtx := context.WithValue(ctx, userKey, "u1")
+			startCtx = context.WithValue(startCtx, statusKey, "pending")
+			endCtx := context.WithValue(ctx, statusKey, "done")
+			endCtx = context.WithValue(endCtx, tenantKey, "t1")
+
+			if tt.endFirst {
+				cap.Emit(endCtx, requestCompleted, requestIDKey.Field("REQ-1"))
+				cap.Emit(startCtx, requestStarted, requestIDKey.Field("REQ-1"))
+			} else {
+				cap.Emit(startCtx, requestStarted, requestIDKey.Field("REQ-1"))
+				cap.Emit(endCtx, requestCompleted, requestIDKey.Field("REQ-1"))
+			}
+
+			time.Sleep(100 * time.Millisecond)
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span, got %d", len(spans))
+			}
+
+			got := make(map[string]string)
+			for _, kv := range spans[0].Attributes() {
+				got[string(kv.Key)] = kv.Value.AsString()
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("span attributes = %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("span attribute %s = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestTraceDurationMetric(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	requestStarted := capitan.NewSignal("request.started", "Request Started")
+	requestCompleted := capitan.NewSignal("request.completed", "Request Completed")
+	requestIDKey := capitan.NewStringKey("request_id")
+
+	schema := Schema{
+		Traces: []TraceSchema{
+			{
+				Start:          "request.started",
+				End:            "request.completed",
+				CorrelationKey: "request_id",
+				SpanName:       "http_request",
+				DurationMetric: "http_request_duration",
+			},
+		},
+	}
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+	sh.WithStaticAttributes(attribute.String("component", "api"))
+
+	if err := sh.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	// Start first, then end first
+	cap.Emit(ctx, requestStarted, requestIDKey.Field("REQ-1"))
+	time.Sleep(20 * time.Millisecond)
+	cap.Emit(ctx, requestCompleted, requestIDKey.Field("REQ-1"))
+	cap.Emit(ctx, requestCompleted, requestIDKey.Field("REQ-2"))
+	time.Sleep(20 * time.Millisecond)
+	cap.Emit(ctx, requestStarted, requestIDKey.Field("REQ-2"))
+	time.Sleep(100 * time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	var found bool
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "http_request_duration" {
+				continue
+			}
+			found = true
+			if m.Unit != "ms" {
+				t.Errorf("unit = %q, want ms", m.Unit)
+			}
+			hist, ok := m.Data.(metricdata.Histogram[float64])
+			if !ok || len(hist.DataPoints) != 1 {
+				t.Fatalf("expected one float64 histogram data point, got %T", m.Data)
+			}
+			dp := hist.DataPoints[0]
+			if dp.Count != 2 {
+				t.Errorf("count = %d, want 2", dp.Count)
+			}
+			// The end-first pair has its end timestamp before its start and is clamped to zero
+			if dp.Sum < 20 {
+				t.Errorf("sum = %vms, want at least 20ms", dp.Sum)
+			}
+			if v, _ := dp.Attributes.Value("component"); v.AsString() != "api" {
+				t.Errorf("component attribute = %q, want api", v.AsString())
+			}
+		}
+	}
+	if !found {
+		t.Fatal("expected http_request_duration histogram")
+	}
+}
+
+func TestPendingSpanCount(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	requestStarted := capitan.NewSignal("request.started", "Request Started")
+	requestCompleted := capitan.NewSignal("request.completed", "Request Completed")
+	requestIDKey := capitan.NewStringKey("request_id")
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), metricnoop.NewMeterProvider(), tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	// No traces configured
+	if starts, ends := sh.PendingSpanCount(); starts != 0 || ends != 0 {
+		t.Errorf("expected 0/0 without traces, got %d/%d", starts, ends)
+	}
+	if total := sh.PendingSpanTotal(); total != 0 {
+		t.Errorf("expected 0 total without traces, got %d", total)
+	}
+
+	err = sh.Apply(Schema{
+		Traces: []TraceSchema{
+			{
+				Start:          "request.started",
+				End:            "request.completed",
+				CorrelationKey: "request_id",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	cap.Emit(ctx, requestStarted, requestIDKey.Field("a"))
+	cap.Emit(ctx, requestStarted, requestIDKey.Field("b"))
+	cap.Emit(ctx, requestCompleted, requestIDKey.Field("c"))
+	cap.Emit(ctx, requestStarted, requestIDKey.Field("d"))
+	cap.Emit(ctx, requestCompleted, requestIDKey.Field("d"))
+
+	time.Sleep(100 * time.Millisecond)
+
+	starts, ends := sh.PendingSpanCount()
+	if starts != 2 || ends != 1 {
+		t.Errorf("expected 2 pending starts and 1 pending end, got %d/%d", starts, ends)
+	}
+	if total := sh.PendingSpanTotal(); total != 3 {
+		t.Errorf("expected 3 pending in total, got %d", total)
+	}
+}
+
+func TestPendingSpanMetric(t *testing.T) {
+	ctx := context.Background()
+	cap := capitan.New()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(ctx)
+
+	requestStarted := capitan.NewSignal("request.started", "Request Started")
+	requestCompleted := capitan.NewSignal("request.completed", "Request Completed")
+	requestIDKey := capitan.NewStringKey("request_id")
+
+	sh, err := New(cap, apertesting.NewMockLoggerProvider(), meterProvider, tracenoop.NewTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create Aperture: %v", err)
+	}
+	defer sh.Close()
+
+	err = sh.Apply(Schema{
+		PendingSpanMetric: true,
+		Traces: []TraceSchema{
+			{
+				Start:          "request.started",
+				End:            "request.completed",
+				CorrelationKey: "request_id",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	cap.Emit(ctx, requestStarted, requestIDKey.Field("a"))
+	cap.Emit(ctx, requestStarted, requestIDKey.Field("b"))
+	cap.Emit(ctx, requestCompleted, requestIDKey.Field("c"))
+
+	time.Sleep(100 * time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("failed to collect metrics: %v", err)
+	}
+
+	got := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != pendingSpanMetricName {
+				continue
+			}
+			gauge, ok := m.Data.(metricdata.Gauge[int64])
+			if !ok {
+				t.Fatalf("expected int64 gauge, got %T", m.Data)
+			}
+			for _, dp := range gauge.DataPoints {
+				kind, _ := dp.Attributes.Value("kind")
+				got[kind.AsString()] = dp.Value
+			}
+		}
+	}
+
+	if got["start"] != 2 || got["end"] != 1 {
+		t.Errorf("expected start=2 end=1, got %v", got)
 	}
 }
@@ -3,8 +3,14 @@ package aperture
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
+	"reflect"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/zoobzio/capitan"
 	"go.opentelemetry.io/otel/attribute"
@@ -29,16 +35,166 @@ func safeUint64ToInt64(v uint64) int64 {
 	return int64(v)
 }
 
+// uint64ToNumeric converts a uint64 metric value without clamping. Values
+// above math.MaxInt64 are returned as float64, which keeps their magnitude but
+// rounds them to 53 bits of precision (to a multiple of 2048 at this range).
+func uint64ToNumeric(v uint64) *numericValue {
+	if v > math.MaxInt64 {
+		return &numericValue{floatValue: float64(v), isFloat: true}
+	}
+	return &numericValue{intValue: int64(v)}
+}
+
+// clampedField describes an unsigned field whose value exceeds math.MaxInt64.
+type clampedField struct {
+	key      string
+	original uint64
+}
+
+// findClampedFields returns the fields that will be clamped by safeUintToInt64
+// or safeUint64ToInt64 when converted to OTEL int64 values.
+func findClampedFields(fields []capitan.Field) []clampedField {
+	var clamped []clampedField
+
+	for _, f := range fields {
+		switch f.Variant() {
+		case capitan.VariantUint:
+			if gf, ok := f.(capitan.GenericField[uint]); ok && uint64(gf.Get()) > math.MaxInt64 {
+				clamped = append(clamped, clampedField{key: f.Key().Name(), original: uint64(gf.Get())})
+			}
+		case capitan.VariantUint64:
+			if gf, ok := f.(capitan.GenericField[uint64]); ok && gf.Get() > math.MaxInt64 {
+				clamped = append(clamped, clampedField{key: f.Key().Name(), original: gf.Get()})
+			}
+		}
+	}
+
+	return clamped
+}
+
+// unserializableField describes a custom-typed field whose value cannot be
+// JSON serialized.
+type unserializableField struct {
+	key    string
+	reason string
+}
+
+// findUnserializableFields returns the custom-typed fields that fieldToJSON
+// will fail to serialize, with the marshal error as the reason. Types that
+// implement LogAttributer or MetricAttributer are not reported.
+func findUnserializableFields(fields []capitan.Field) []unserializableField {
+	type valueGetter interface {
+		Value() any
+	}
+
+	var failed []unserializableField
+
+	for _, f := range fields {
+		if isBuiltinVariant(f.Variant()) {
+			continue
+		}
+		vg, ok := f.(valueGetter)
+		if !ok {
+			continue
+		}
+		switch vg.Value().(type) {
+		case LogAttributer, MetricAttributer:
+			continue
+		}
+		if _, err := json.Marshal(vg.Value()); err != nil {
+			failed = append(failed, unserializableField{key: f.Key().Name(), reason: err.Error()})
+		}
+	}
+
+	return failed
+}
+
+// isBuiltinVariant reports whether v is a capitan variant converted natively
+// rather than JSON serialized.
+func isBuiltinVariant(v capitan.Variant) bool {
+	switch v {
+	case capitan.VariantString, capitan.VariantInt, capitan.VariantInt32, capitan.VariantInt64,
+		capitan.VariantUint, capitan.VariantUint32, capitan.VariantUint64,
+		capitan.VariantFloat32, capitan.VariantFloat64, capitan.VariantBool,
+		capitan.VariantTime, capitan.VariantDuration, capitan.VariantBytes, capitan.VariantError:
+		return true
+	default:
+		return false
+	}
+}
+
+// LogAttributer is implemented by custom field types that convert themselves
+// to OTEL log attributes, in place of JSON serialization. key is the field key
+// name; implementations typically use it as the attribute name or prefix.
+type LogAttributer interface {
+	OTELAttributes(key string) []log.KeyValue
+}
+
+// MetricAttributer is implemented by custom field types that convert themselves
+// to OTEL metric attributes, in place of JSON serialization. key is the field
+// key name after any attribute_rename mapping.
+type MetricAttributer interface {
+	OTELMetricAttributes(key string) []attribute.KeyValue
+}
+
+// errorCoder is implemented by errors that carry a machine-readable code.
+type errorCoder interface {
+	Code() string
+}
+
+// errorDetails returns the Go type name of err and, if err or any error in its
+// chain implements errorCoder, that code. typeName is empty for a nil error.
+func errorDetails(err error) (typeName, code string, hasCode bool) {
+	if err == nil {
+		return "", "", false
+	}
+
+	typeName = reflect.TypeOf(err).String()
+	var coder errorCoder
+	if errors.As(err, &coder) {
+		return typeName, coder.Code(), true
+	}
+	return typeName, "", false
+}
+
 // transformResult holds the result of field transformation.
 type transformResult struct {
 	attrs []log.KeyValue
 }
 
+// withoutFields returns fields minus those whose key is in exclude. The input
+// slice is returned as is when nothing is excluded.
+func withoutFields(fields []capitan.Field, exclude map[string]struct{}) []capitan.Field {
+	if len(exclude) == 0 {
+		return fields
+	}
+
+	for i, f := range fields {
+		if _, ok := exclude[f.Key().Name()]; !ok {
+			continue
+		}
+
+		// First excluded field: copy the kept prefix and filter the rest
+		kept := slices.Clone(fields[:i])
+		for _, rest := range fields[i+1:] {
+			if _, ok := exclude[rest.Key().Name()]; !ok {
+				kept = append(kept, rest)
+			}
+		}
+		return kept
+	}
+	return fields
+}
+
 // fieldsToAttributes transforms capitan fields to OTEL log attributes.
 //
 // Built-in capitan field variants are converted to appropriate OTEL types.
-// Custom field types are JSON serialized as strings.
-func fieldsToAttributes(fields []capitan.Field) transformResult {
+// Custom field types implementing [LogAttributer] supply their own attributes;
+// other custom types are JSON serialized as strings. Those that fail to
+// serialize are dropped unless keepUnserializable is true. If structuredErrors
+// is true, error fields also produce <key>.type and <key>.code attributes.
+// If maxBytes is positive, longer string and bytes values are truncated.
+func fieldsToAttributes(fields []capitan.Field, structuredErrors, keepUnserializable bool, maxBytes int) transformResult {
 	result := transformResult{
 		attrs: make([]log.KeyValue, 0, len(fields)),
 	}
@@ -117,22 +273,41 @@ func fieldsToAttributes(fields []capitan.Field) transformResult {
 		case capitan.VariantError:
 			if gf, ok := f.(capitan.GenericField[error]); ok {
 				result.attrs = append(result.attrs, log.String(key, gf.Get().Error()))
+				if !structuredErrors {
+					break
+				}
+				if typeName, code, hasCode := errorDetails(gf.Get()); typeName != "" {
+					result.attrs = append(result.attrs, log.String(key+".type", typeName))
+					if hasCode {
+						result.attrs = append(result.attrs, log.String(key+".code", code))
+					}
+				}
 			}
 
 		default:
-			// Custom types: JSON serialize
-			if jsonStr := fieldToJSON(f); jsonStr != "" {
+			// Custom types: self-described, else JSON serialize
+			if la, ok := f.Value().(LogAttributer); ok {
+				result.attrs = append(result.attrs, la.OTELAttributes(key)...)
+				break
+			}
+			if jsonStr := fieldToJSON(f, keepUnserializable); jsonStr != "" {
 				result.attrs = append(result.attrs, log.String(key, jsonStr))
 			}
 		}
 	}
 
+	if maxBytes > 0 && truncateLogAttributes(result.attrs, maxBytes) {
+		result.attrs = append(result.attrs, log.Bool(truncatedAttributeKey, true))
+	}
+
 	return result
 }
 
 // fieldToJSON attempts to JSON serialize a field's value.
-// Returns empty string if serialization fails.
-func fieldToJSON(f capitan.Field) string {
+// Returns empty string if serialization fails, or a <unserializable:Type>
+// placeholder if keepUnserializable is true. The placeholder names only the
+// type, so fields hidden from JSON are not leaked by formatting the value.
+func fieldToJSON(f capitan.Field, keepUnserializable bool) string {
 	// Try to get the underlying value via reflection on GenericField
 	// We use a type switch on common interface patterns
 	type valueGetter interface {
@@ -140,21 +315,67 @@ func fieldToJSON(f capitan.Field) string {
 	}
 
 	if vg, ok := f.(valueGetter); ok {
-		if data, err := json.Marshal(vg.Value()); err == nil {
+		data, err := json.Marshal(vg.Value())
+		if err == nil {
 			return string(data)
 		}
+		if keepUnserializable {
+			return fmt.Sprintf("<unserializable:%T>", vg.Value())
+		}
 	}
 
 	// Fallback: try to marshal the field itself (unlikely to work well)
 	return ""
 }
 
+// fieldToNumeric attempts to coerce a custom field's value to a numeric value.
+//
+// The value is read through the same Value() accessor used by fieldToJSON and
+// accepted if its underlying kind is an integer or float (e.g. type Money int64).
+// Unsigned values exceeding math.MaxInt64 are returned as float64. Returns nil
+// otherwise.
+func fieldToNumeric(f capitan.Field) *numericValue {
+	type valueGetter interface {
+		Value() any
+	}
+
+	vg, ok := f.(valueGetter)
+	if !ok {
+		return nil
+	}
+
+	rv := reflect.ValueOf(vg.Value())
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &numericValue{intValue: rv.Int()}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return uint64ToNumeric(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return &numericValue{floatValue: rv.Float(), isFloat: true}
+	default:
+		return nil
+	}
+}
+
 // fieldsToMetricAttributes transforms capitan fields to OTEL metric attributes.
-func fieldsToMetricAttributes(fields []capitan.Field) []attribute.KeyValue {
+// If allow is non-nil, only fields whose key it contains are converted.
+// Field keys found in rename are emitted under the mapped name; others pass through.
+// Custom types implementing [MetricAttributer] supply their own attributes;
+// others that fail to serialize are handled as in fieldsToAttributes.
+// If maxBytes is positive, longer string values are truncated.
+func fieldsToMetricAttributes(fields []capitan.Field, allow map[string]struct{}, rename map[string]string, keepUnserializable bool, maxBytes int) []attribute.KeyValue {
 	attrs := make([]attribute.KeyValue, 0, len(fields))
 
 	for _, f := range fields {
 		key := f.Key().Name()
+		if allow != nil {
+			if _, ok := allow[key]; !ok {
+				continue
+			}
+		}
+		if to, ok := rename[key]; ok {
+			key = to
+		}
 
 		switch f.Variant() {
 		case capitan.VariantString:
@@ -228,16 +449,96 @@ func fieldsToMetricAttributes(fields []capitan.Field) []attribute.KeyValue {
 			}
 
 		default:
-			// Custom types: JSON serialize for metrics too
-			if jsonStr := fieldToJSON(f); jsonStr != "" {
+			// Custom types: self-described, else JSON serialize for metrics too
+			if ma, ok := f.Value().(MetricAttributer); ok {
+				attrs = append(attrs, ma.OTELMetricAttributes(key)...)
+				break
+			}
+			if jsonStr := fieldToJSON(f, keepUnserializable); jsonStr != "" {
 				attrs = append(attrs, attribute.String(key, jsonStr))
 			}
 		}
 	}
 
+	if maxBytes > 0 && truncateMetricAttributes(attrs, maxBytes) {
+		attrs = append(attrs, attribute.Bool(truncatedAttributeKey, true))
+	}
+
 	return attrs
 }
 
+// truncatedAttributeKey marks records with a value cut by max_attribute_bytes.
+const truncatedAttributeKey = "aperture.truncated"
+
+// truncationMarker is appended to values cut by max_attribute_bytes.
+const truncationMarker = "..."
+
+// truncateString cuts s to at most maxBytes bytes, backing off to a rune
+// boundary, and appends the truncation marker. Reports whether s was cut.
+func truncateString(s string, maxBytes int) (string, bool) {
+	if len(s) <= maxBytes {
+		return s, false
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + truncationMarker, true
+}
+
+// truncateLogAttributes truncates string and bytes values in place.
+// Reports whether any value was cut.
+func truncateLogAttributes(attrs []log.KeyValue, maxBytes int) bool {
+	truncated := false
+	for i, kv := range attrs {
+		switch kv.Value.Kind() {
+		case log.KindString:
+			if v, cut := truncateString(kv.Value.AsString(), maxBytes); cut {
+				attrs[i].Value = log.StringValue(v)
+				truncated = true
+			}
+		case log.KindBytes:
+			if b := kv.Value.AsBytes(); len(b) > maxBytes {
+				attrs[i].Value = log.BytesValue(append(slices.Clip(b[:maxBytes]), truncationMarker...))
+				truncated = true
+			}
+		}
+	}
+	return truncated
+}
+
+// truncateMetricAttributes truncates string values in place.
+// Reports whether any value was cut.
+func truncateMetricAttributes(attrs []attribute.KeyValue, maxBytes int) bool {
+	truncated := false
+	for i, kv := range attrs {
+		if kv.Value.Type() != attribute.STRING {
+			continue
+		}
+		if v, cut := truncateString(kv.Value.AsString(), maxBytes); cut {
+			attrs[i] = attribute.String(string(kv.Key), v)
+			truncated = true
+		}
+	}
+	return truncated
+}
+
+// sortLogAttributes sorts attrs by key in place. The sort is stable, so of two
+// attributes with the same key the later one still wins.
+func sortLogAttributes(attrs []log.KeyValue) {
+	slices.SortStableFunc(attrs, func(a, b log.KeyValue) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+}
+
+// sortAttributes sorts attrs by key in place, keeping same-key order as
+// sortLogAttributes does.
+func sortAttributes(attrs []attribute.KeyValue) {
+	slices.SortStableFunc(attrs, func(a, b attribute.KeyValue) int {
+		return strings.Compare(string(a.Key), string(b.Key))
+	})
+}
+
 // extractContextValuesForLogs extracts values from context and converts them to log attributes.
 // Values that don't exist in context are skipped.
 func extractContextValuesForLogs(ctx context.Context, keys []ContextKey) []log.KeyValue {
@@ -252,6 +553,11 @@ func extractContextValuesForLogs(ctx context.Context, keys []ContextKey) []log.K
 			continue
 		}
 
+		if ck.logTransform != nil {
+			attrs = append(attrs, ck.logTransform(val)...)
+			continue
+		}
+
 		// Convert value to appropriate OTEL log attribute type
 		switch v := val.(type) {
 		case string:
@@ -301,6 +607,11 @@ func extractContextValuesForMetrics(ctx context.Context, keys []ContextKey) []at
 			continue
 		}
 
+		if ck.metricTransform != nil {
+			attrs = append(attrs, ck.metricTransform(val)...)
+			continue
+		}
+
 		// Convert value to appropriate OTEL metric attribute type
 		switch v := val.(type) {
 		case string:
@@ -335,3 +646,57 @@ func extractContextValuesForMetrics(ctx context.Context, keys []ContextKey) []at
 
 	return attrs
 }
+
+// attributesToLog converts OTEL attributes to OTEL log attributes.
+// Slice values are carried as log slices of the matching element type.
+func attributesToLog(attrs []attribute.KeyValue) []log.KeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	out := make([]log.KeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		key := string(kv.Key)
+
+		switch kv.Value.Type() {
+		case attribute.BOOL:
+			out = append(out, log.Bool(key, kv.Value.AsBool()))
+		case attribute.INT64:
+			out = append(out, log.Int64(key, kv.Value.AsInt64()))
+		case attribute.FLOAT64:
+			out = append(out, log.Float64(key, kv.Value.AsFloat64()))
+		case attribute.STRING:
+			out = append(out, log.String(key, kv.Value.AsString()))
+		case attribute.BOOLSLICE:
+			vals := kv.Value.AsBoolSlice()
+			items := make([]log.Value, len(vals))
+			for i, v := range vals {
+				items[i] = log.BoolValue(v)
+			}
+			out = append(out, log.Slice(key, items...))
+		case attribute.INT64SLICE:
+			vals := kv.Value.AsInt64Slice()
+			items := make([]log.Value, len(vals))
+			for i, v := range vals {
+				items[i] = log.Int64Value(v)
+			}
+			out = append(out, log.Slice(key, items...))
+		case attribute.FLOAT64SLICE:
+			vals := kv.Value.AsFloat64Slice()
+			items := make([]log.Value, len(vals))
+			for i, v := range vals {
+				items[i] = log.Float64Value(v)
+			}
+			out = append(out, log.Slice(key, items...))
+		case attribute.STRINGSLICE:
+			vals := kv.Value.AsStringSlice()
+			items := make([]log.Value, len(vals))
+			for i, v := range vals {
+				items[i] = log.StringValue(v)
+			}
+			out = append(out, log.Slice(key, items...))
+		}
+	}
+
+	return out
+}
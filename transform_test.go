@@ -3,10 +3,12 @@ package aperture
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/zoobzio/capitan"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/log"
 )
 
@@ -134,7 +136,7 @@ func TestFieldsToAttributes(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := fieldsToAttributes(tt.fields)
+			result := fieldsToAttributes(tt.fields, false, false, 0)
 
 			if len(result.attrs) != tt.wantLen {
 				t.Errorf("expected %d attributes, got %d", tt.wantLen, len(result.attrs))
@@ -166,7 +168,7 @@ func TestFieldsToAttributesAllTypes(t *testing.T) {
 		capitan.NewErrorKey("error").Field(errors.New("err")),
 	}
 
-	result := fieldsToAttributes(fields)
+	result := fieldsToAttributes(fields, false, false, 0)
 
 	// All 14 built-in types should be converted
 	if len(result.attrs) != 14 {
@@ -288,7 +290,7 @@ func TestFieldsToMetricAttributes(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			attrs := fieldsToMetricAttributes(tt.fields)
+			attrs := fieldsToMetricAttributes(tt.fields, nil, nil, false, 0)
 
 			if len(attrs) != tt.wantLen {
 				t.Errorf("expected %d metric attributes, got %d", tt.wantLen, len(attrs))
@@ -316,7 +318,7 @@ func TestFieldsToMetricAttributes_AllBuiltinTypes(t *testing.T) {
 		capitan.NewErrorKey("error").Field(errors.New("err")),
 	}
 
-	attrs := fieldsToMetricAttributes(fields)
+	attrs := fieldsToMetricAttributes(fields, nil, nil, false, 0)
 
 	// All 14 built-in types should be converted
 	if len(attrs) != 14 {
@@ -679,3 +681,342 @@ func TestExtractContextValuesForMetrics_AllTypes(t *testing.T) {
 		t.Error("missing unsupported attribute (should be JSON serialized)")
 	}
 }
+
+func TestAttributesToLog(t *testing.T) {
+	if got := attributesToLog(nil); got != nil {
+		t.Errorf("expected nil for no attributes, got %v", got)
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("component", "billing"),
+		attribute.Int64("shard", 3),
+		attribute.Float64("weight", 0.5),
+		attribute.Bool("canary", true),
+		attribute.StringSlice("zones", []string{"a", "b"}),
+	}
+
+	got := attributesToLog(attrs)
+	if len(got) != len(attrs) {
+		t.Fatalf("expected %d attributes, got %d", len(attrs), len(got))
+	}
+
+	if got[0].Key != "component" || got[0].Value.AsString() != "billing" {
+		t.Errorf("component = %v", got[0])
+	}
+	if got[1].Key != "shard" || got[1].Value.AsInt64() != 3 {
+		t.Errorf("shard = %v", got[1])
+	}
+	if got[2].Key != "weight" || got[2].Value.AsFloat64() != 0.5 {
+		t.Errorf("weight = %v", got[2])
+	}
+	if got[3].Key != "canary" || !got[3].Value.AsBool() {
+		t.Errorf("canary = %v", got[3])
+	}
+	if got[4].Key != "zones" || got[4].Value.Kind() != log.KindSlice || len(got[4].Value.AsSlice()) != 2 {
+		t.Errorf("zones = %v", got[4])
+	}
+}
+
+func TestFieldsToMetricAttributes_Rename(t *testing.T) {
+	fields := []capitan.Field{
+		capitan.NewStringKey("order_status").Field("paid"),
+		capitan.NewStringKey("region").Field("eu"),
+	}
+
+	attrs := fieldsToMetricAttributes(fields, nil, map[string]string{"order_status": "status"}, false, 0)
+
+	if len(attrs) != 2 {
+		t.Fatalf("expected 2 attributes, got %d", len(attrs))
+	}
+	if attrs[0].Key != "status" || attrs[0].Value.AsString() != "paid" {
+		t.Errorf("attrs[0] = %s=%s, want status=paid", attrs[0].Key, attrs[0].Value.AsString())
+	}
+	if attrs[1].Key != "region" {
+		t.Errorf("attrs[1].Key = %s, want region", attrs[1].Key)
+	}
+}
+
+func TestFieldsToMetricAttributes_Allow(t *testing.T) {
+	fields := []capitan.Field{
+		capitan.NewStringKey("order_status").Field("paid"),
+		capitan.NewStringKey("user_id").Field("user-1"),
+	}
+
+	attrs := fieldsToMetricAttributes(fields, map[string]struct{}{"order_status": {}}, nil, false, 0)
+
+	if len(attrs) != 1 || attrs[0].Key != "order_status" {
+		t.Errorf("expected only order_status, got %v", attrs)
+	}
+}
+
+func TestWithoutFields(t *testing.T) {
+	fields := []capitan.Field{
+		capitan.NewStringKey("user_id").Field("user-1"),
+		capitan.NewStringKey("password").Field("hunter2"),
+		capitan.NewStringKey("order_status").Field("paid"),
+		capitan.NewStringKey("token").Field("abc"),
+	}
+
+	kept := withoutFields(fields, map[string]struct{}{"password": {}, "token": {}})
+	if len(kept) != 2 || kept[0].Key().Name() != "user_id" || kept[1].Key().Name() != "order_status" {
+		t.Errorf("expected [user_id order_status], got %d fields", len(kept))
+	}
+	if len(fields) != 4 || fields[1].Key().Name() != "password" {
+		t.Error("withoutFields modified its input")
+	}
+
+	// Nothing to exclude returns the input unchanged
+	if got := withoutFields(fields, nil); len(got) != 4 {
+		t.Errorf("expected 4 fields with no exclusions, got %d", len(got))
+	}
+	if got := withoutFields(fields, map[string]struct{}{"missing": {}}); len(got) != 4 {
+		t.Errorf("expected 4 fields when no key matches, got %d", len(got))
+	}
+}
+
+// codedError is a test error carrying a machine-readable code.
+type codedError struct{ code string }
+
+func (e *codedError) Error() string { return "coded: " + e.code }
+func (e *codedError) Code() string  { return e.code }
+
+func TestFieldsToAttributes_StructuredErrors(t *testing.T) {
+	wrapped := fmt.Errorf("charging card: %w", &codedError{code: "card_declined"})
+	fields := []capitan.Field{
+		capitan.NewErrorKey("err").Field(wrapped),
+		capitan.NewErrorKey("plain").Field(errors.New("boom")),
+	}
+
+	// Default: message only
+	result := fieldsToAttributes(fields, false, false, 0)
+	if len(result.attrs) != 2 {
+		t.Fatalf("expected 2 attributes without structured errors, got %d", len(result.attrs))
+	}
+
+	result = fieldsToAttributes(fields, true, false, 0)
+	want := map[string]string{
+		"err":        "charging card: coded: card_declined",
+		"err.type":   "*fmt.wrapError",
+		"err.code":   "card_declined",
+		"plain":      "boom",
+		"plain.type": "*errors.errorString",
+	}
+	if len(result.attrs) != len(want) {
+		t.Fatalf("expected %d attributes, got %d: %v", len(want), len(result.attrs), result.attrs)
+	}
+	for _, kv := range result.attrs {
+		if want[kv.Key] != kv.Value.AsString() {
+			t.Errorf("%s = %q, want %q", kv.Key, kv.Value.AsString(), want[kv.Key])
+		}
+	}
+}
+
+func TestErrorDetails(t *testing.T) {
+	typeName, code, hasCode := errorDetails(&codedError{code: "E42"})
+	if typeName != "*aperture.codedError" || code != "E42" || !hasCode {
+		t.Errorf("errorDetails = (%q, %q, %v), want (*aperture.codedError, E42, true)", typeName, code, hasCode)
+	}
+
+	if typeName, _, _ := errorDetails(nil); typeName != "" {
+		t.Errorf("expected empty type name for nil error, got %q", typeName)
+	}
+}
+
+func TestFieldToJSON_Unserializable(t *testing.T) {
+	field := capitan.NewKey[chan int]("events", "test.Chan").Field(make(chan int))
+	fields := []capitan.Field{field}
+
+	// Default: dropped
+	if got := fieldToJSON(field, false); got != "" {
+		t.Errorf("fieldToJSON() = %q, want empty", got)
+	}
+	if result := fieldsToAttributes(fields, false, false, 0); len(result.attrs) != 0 {
+		t.Errorf("expected field to be dropped from logs, got %v", result.attrs)
+	}
+	if attrs := fieldsToMetricAttributes(fields, nil, nil, false, 0); len(attrs) != 0 {
+		t.Errorf("expected field to be dropped from metrics, got %v", attrs)
+	}
+
+	// Kept as a placeholder naming the type
+	want := "<unserializable:chan int>"
+	if got := fieldToJSON(field, true); got != want {
+		t.Errorf("fieldToJSON() = %q, want %q", got, want)
+	}
+	result := fieldsToAttributes(fields, false, true, 0)
+	if len(result.attrs) != 1 || result.attrs[0].Value.AsString() != want {
+		t.Errorf("expected log placeholder %q, got %v", want, result.attrs)
+	}
+	attrs := fieldsToMetricAttributes(fields, nil, nil, true, 0)
+	if len(attrs) != 1 || attrs[0].Value.AsString() != want {
+		t.Errorf("expected metric placeholder %q, got %v", want, attrs)
+	}
+}
+
+// geoPoint is a custom field type that converts itself to OTEL attributes.
+type geoPoint struct {
+	Lat, Lon float64
+}
+
+func (p geoPoint) OTELAttributes(key string) []log.KeyValue {
+	return []log.KeyValue{log.Float64(key+".lat", p.Lat), log.Float64(key+".lon", p.Lon)}
+}
+
+func (p geoPoint) OTELMetricAttributes(key string) []attribute.KeyValue {
+	return []attribute.KeyValue{attribute.Float64(key+".lat", p.Lat)}
+}
+
+func TestFieldsToAttributes_Attributer(t *testing.T) {
+	fields := []capitan.Field{
+		capitan.NewKey[geoPoint]("origin", "test.GeoPoint").Field(geoPoint{Lat: 51.5, Lon: -0.1}),
+	}
+
+	result := fieldsToAttributes(fields, false, false, 0)
+	if len(result.attrs) != 2 {
+		t.Fatalf("expected 2 log attributes, got %v", result.attrs)
+	}
+	if result.attrs[0].Key != "origin.lat" || result.attrs[0].Value.AsFloat64() != 51.5 {
+		t.Errorf("attrs[0] = %v, want origin.lat=51.5", result.attrs[0])
+	}
+	if result.attrs[1].Key != "origin.lon" || result.attrs[1].Value.AsFloat64() != -0.1 {
+		t.Errorf("attrs[1] = %v, want origin.lon=-0.1", result.attrs[1])
+	}
+
+	attrs := fieldsToMetricAttributes(fields, nil, map[string]string{"origin": "from"}, false, 0)
+	if len(attrs) != 1 || attrs[0].Key != "from.lat" || attrs[0].Value.AsFloat64() != 51.5 {
+		t.Errorf("expected metric attribute from.lat=51.5, got %v", attrs)
+	}
+
+	if got := findUnserializableFields(fields); len(got) != 0 {
+		t.Errorf("expected no unserializable fields, got %v", got)
+	}
+}
+
+func TestFieldsToAttributes_MaxBytes(t *testing.T) {
+	fields := []capitan.Field{
+		capitan.NewStringKey("short").Field("abc"),
+		capitan.NewStringKey("long").Field("abcdefgh"),
+		capitan.NewStringKey("utf8").Field("aéé"),
+		capitan.NewBytesKey("payload").Field([]byte("0123456789")),
+		capitan.NewInt64Key("count").Field(123456789),
+	}
+
+	result := fieldsToAttributes(fields, false, false, 4)
+	got := make(map[string]log.Value)
+	for _, kv := range result.attrs {
+		got[kv.Key] = kv.Value
+	}
+
+	if v := got["short"].AsString(); v != "abc" {
+		t.Errorf("short = %q, want unchanged", v)
+	}
+	if v := got["long"].AsString(); v != "abcd..." {
+		t.Errorf("long = %q, want %q", v, "abcd...")
+	}
+	// "aéé" is 5 bytes; cutting at 4 would split the second é
+	if v := got["utf8"].AsString(); v != "aé..." {
+		t.Errorf("utf8 = %q, want %q", v, "aé...")
+	}
+	if v := string(got["payload"].AsBytes()); v != "0123..." {
+		t.Errorf("payload = %q, want %q", v, "0123...")
+	}
+	if v := got["count"].AsInt64(); v != 123456789 {
+		t.Errorf("count = %d, want unchanged", v)
+	}
+	if !got[truncatedAttributeKey].AsBool() {
+		t.Errorf("expected %s=true, got %v", truncatedAttributeKey, result.attrs)
+	}
+
+	attrs := fieldsToMetricAttributes(fields, nil, nil, false, 4)
+	metricGot := make(map[attribute.Key]attribute.Value)
+	for _, kv := range attrs {
+		metricGot[kv.Key] = kv.Value
+	}
+	if v := metricGot["long"].AsString(); v != "abcd..." {
+		t.Errorf("metric long = %q, want %q", v, "abcd...")
+	}
+	if v := metricGot["payload"].AsString(); v != "0123..." {
+		t.Errorf("metric payload = %q, want %q", v, "0123...")
+	}
+	if !metricGot[truncatedAttributeKey].AsBool() {
+		t.Errorf("expected metric %s=true, got %v", truncatedAttributeKey, attrs)
+	}
+
+	// Nothing over the limit adds no marker
+	result = fieldsToAttributes(fields[:1], false, false, 4)
+	if len(result.attrs) != 1 {
+		t.Errorf("expected no truncation marker, got %v", result.attrs)
+	}
+	if attrs := fieldsToMetricAttributes(fields[:1], nil, nil, false, 4); len(attrs) != 1 {
+		t.Errorf("expected no metric truncation marker, got %v", attrs)
+	}
+}
+
+func TestFindUnserializableFields(t *testing.T) {
+	fields := []capitan.Field{
+		capitan.NewStringKey("name").Field("ok"),
+		capitan.NewKey[struct{ A int }]("good", "test.Good").Field(struct{ A int }{A: 1}),
+		capitan.NewKey[func()]("bad", "test.Func").Field(func() {}),
+	}
+
+	got := findUnserializableFields(fields)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 unserializable field, got %v", got)
+	}
+	if got[0].key != "bad" || got[0].reason == "" {
+		t.Errorf("findUnserializableFields()[0] = %+v, want key bad with a reason", got[0])
+	}
+}
+
+// requestMeta is a struct context value expanded by a transformer.
+type requestMeta struct {
+	ID    string
+	Route string
+}
+
+func TestExtractContextValues_Transformer(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ctxKeyString("meta"), requestMeta{ID: "req-1", Route: "/orders"})
+	ctx = context.WithValue(ctx, ctxKeyString("user"), "user-1")
+
+	logKeys := []ContextKey{
+		{
+			Key:  ctxKeyString("meta"),
+			Name: "meta",
+			logTransform: func(v any) []log.KeyValue {
+				meta := v.(requestMeta) //nolint:errcheck // test value
+				return []log.KeyValue{log.String("request.id", meta.ID), log.String("request.route", meta.Route)}
+			},
+		},
+		{Key: ctxKeyString("user"), Name: "user"},
+	}
+
+	logAttrs := extractContextValuesForLogs(ctx, logKeys)
+	if len(logAttrs) != 3 {
+		t.Fatalf("expected 3 log attributes, got %d: %v", len(logAttrs), logAttrs)
+	}
+	if logAttrs[0].Key != "request.id" || logAttrs[0].Value.AsString() != "req-1" {
+		t.Errorf("logAttrs[0] = %s=%s, want request.id=req-1", logAttrs[0].Key, logAttrs[0].Value.AsString())
+	}
+	if logAttrs[1].Key != "request.route" || logAttrs[2].Key != "user" {
+		t.Errorf("unexpected log attribute order: %v", logAttrs)
+	}
+
+	metricKeys := []ContextKey{
+		{
+			Key:  ctxKeyString("meta"),
+			Name: "meta",
+			metricTransform: func(v any) []attribute.KeyValue {
+				return []attribute.KeyValue{attribute.String("route", v.(requestMeta).Route)} //nolint:errcheck // test value
+			},
+		},
+	}
+
+	metricAttrs := extractContextValuesForMetrics(ctx, metricKeys)
+	if len(metricAttrs) != 1 || metricAttrs[0].Key != "route" || metricAttrs[0].Value.AsString() != "/orders" {
+		t.Errorf("expected route=/orders, got %v", metricAttrs)
+	}
+
+	// Transformers are not consulted for absent values
+	if attrs := extractContextValuesForLogs(context.Background(), logKeys); len(attrs) != 0 {
+		t.Errorf("expected no attributes for empty context, got %v", attrs)
+	}
+}